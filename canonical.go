@@ -0,0 +1,121 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// canonicalJSON rewrites the JSON-encoded document in data so that
+// identical documents always produce identical bytes:
+//   - Object keys are sorted, including those of extensions and extras.
+//   - Numbers are formatted with the shortest representation that round-trips.
+//   - Required arrays and objects are never encoded as null.
+func canonicalJSON(data []byte, prefix, indent string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	v = canonicalValue(v)
+	if root, ok := v.(map[string]any); ok {
+		canonicalDefaults(root)
+	}
+	if len(prefix) > 0 || len(indent) > 0 {
+		return json.MarshalIndent(v, prefix, indent)
+	}
+	return json.Marshal(v)
+}
+
+// canonicalValue normalizes the numbers of v.
+// Objects keys don't need to be sorted, json.Marshal already does it for maps.
+func canonicalValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = canonicalValue(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = canonicalValue(e)
+		}
+	case json.Number:
+		return canonicalNumber(v)
+	}
+	return v
+}
+
+// canonicalNumber formats n as encoding/json does with float64 values,
+// except for integers that cannot be exactly represented as a float64.
+func canonicalNumber(n json.Number) json.Number {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		// Integers are kept as is, as not all of them fit in a float64.
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return n
+	}
+	if f == 0 {
+		// Normalize negative zero.
+		f = 0
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return n
+	}
+	return json.Number(b)
+}
+
+// canonicalDefaults replaces the required arrays and objects
+// that have been encoded as null with empty ones.
+func canonicalDefaults(doc map[string]any) {
+	for _, mesh := range canonicalObjects(doc["meshes"]) {
+		canonicalArray(mesh, "primitives")
+		for _, prim := range canonicalObjects(mesh["primitives"]) {
+			canonicalObject(prim, "attributes")
+			if targets, ok := prim["targets"].([]any); ok {
+				for i, t := range targets {
+					if t == nil {
+						targets[i] = map[string]any{}
+					}
+				}
+			}
+		}
+	}
+	for _, skin := range canonicalObjects(doc["skins"]) {
+		canonicalArray(skin, "joints")
+	}
+	for _, anim := range canonicalObjects(doc["animations"]) {
+		canonicalArray(anim, "channels")
+		canonicalArray(anim, "samplers")
+	}
+}
+
+func canonicalObjects(v any) []map[string]any {
+	arr, _ := v.([]any)
+	objs := make([]map[string]any, 0, len(arr))
+	for _, e := range arr {
+		if obj, ok := e.(map[string]any); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+func canonicalArray(obj map[string]any, key string) {
+	if v, ok := obj[key]; ok && v == nil {
+		obj[key] = []any{}
+	}
+}
+
+func canonicalObject(obj map[string]any, key string) {
+	if v, ok := obj[key]; ok && v == nil {
+		obj[key] = map[string]any{}
+	}
+}
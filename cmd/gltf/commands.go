@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func runInfo(args []string, w io.Writer) error {
+	args, err := parseArgs(flag.NewFlagSet("info", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	doc, err := gltf.Open(args[0])
+	if err != nil {
+		return err
+	}
+	var bufferSize int
+	for _, b := range doc.Buffers {
+		bufferSize += b.ByteLength
+	}
+	fmt.Fprintf(w, "version:     %s\n", doc.Asset.Version)
+	if doc.Asset.Generator != "" {
+		fmt.Fprintf(w, "generator:   %s\n", doc.Asset.Generator)
+	}
+	fmt.Fprintf(w, "scenes:      %d\n", len(doc.Scenes))
+	fmt.Fprintf(w, "nodes:       %d\n", len(doc.Nodes))
+	fmt.Fprintf(w, "meshes:      %d\n", len(doc.Meshes))
+	fmt.Fprintf(w, "materials:   %d\n", len(doc.Materials))
+	fmt.Fprintf(w, "textures:    %d\n", len(doc.Textures))
+	fmt.Fprintf(w, "images:      %d\n", len(doc.Images))
+	fmt.Fprintf(w, "samplers:    %d\n", len(doc.Samplers))
+	fmt.Fprintf(w, "cameras:     %d\n", len(doc.Cameras))
+	fmt.Fprintf(w, "skins:       %d\n", len(doc.Skins))
+	fmt.Fprintf(w, "animations:  %d\n", len(doc.Animations))
+	fmt.Fprintf(w, "accessors:   %d\n", len(doc.Accessors))
+	fmt.Fprintf(w, "bufferViews: %d\n", len(doc.BufferViews))
+	fmt.Fprintf(w, "buffers:     %d (%d bytes)\n", len(doc.Buffers), bufferSize)
+	if len(doc.ExtensionsUsed) > 0 {
+		fmt.Fprintf(w, "extensionsUsed:     %s\n", strings.Join(doc.ExtensionsUsed, ", "))
+	}
+	if len(doc.ExtensionsRequired) > 0 {
+		fmt.Fprintf(w, "extensionsRequired: %s\n", strings.Join(doc.ExtensionsRequired, ", "))
+	}
+	return nil
+}
+
+func runValidate(args []string, w io.Writer) error {
+	args, err := parseArgs(flag.NewFlagSet("validate", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	var failed bool
+	for _, name := range args {
+		if err := validate(name); err != nil {
+			failed = true
+			fmt.Fprintf(w, "%s: %v\n", name, err)
+		} else {
+			fmt.Fprintf(w, "%s: ok\n", name)
+		}
+	}
+	if failed {
+		return errors.New("validation failed")
+	}
+	return nil
+}
+
+func validate(name string) error {
+	doc, err := gltf.Open(name)
+	if err != nil {
+		return err
+	}
+	for i, acr := range doc.Accessors {
+		if _, err := modeler.ReadAccessor(doc, acr, nil); err != nil {
+			return fmt.Errorf("accessor %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// runPack converts a glTF into a GLB that contains all its buffers and images
+// in the binary chunk.
+func runPack(args []string, w io.Writer) error {
+	args, err := parseArgs(flag.NewFlagSet("pack", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	doc, err := gltf.Open(args[0])
+	if err != nil {
+		return err
+	}
+	for _, im := range doc.Images {
+		if im.Name == "" && im.BufferView == nil && !im.IsEmbeddedResource() && !isRemote(im.URI) {
+			// Keep the file name for reference.
+			im.Name = path.Base(im.URI)
+		}
+	}
+	f, err := os.Create(args[1])
+	if err != nil {
+		return err
+	}
+	enc := gltf.NewEncoder(f)
+	enc.Embed = gltf.EmbedPacked
+	enc.Source = os.DirFS(filepath.Dir(args[0]))
+	if err := enc.Encode(doc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runUnpack converts a GLB into a glTF whose binary chunk
+// is written as an external buffer next to it.
+func runUnpack(args []string, w io.Writer) error {
+	args, err := parseArgs(flag.NewFlagSet("unpack", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	doc, err := gltf.Open(args[0])
+	if err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(filepath.Base(args[1]), filepath.Ext(args[1]))
+	for i, b := range doc.Buffers {
+		if b.URI != "" && !b.IsEmbeddedResource() {
+			continue
+		}
+		b.URI = base + ".bin"
+		if i > 0 {
+			b.URI = base + strconv.Itoa(i) + ".bin"
+		}
+	}
+	return gltf.Save(doc, args[1])
+}
+
+func runPretty(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("pretty", flag.ContinueOnError)
+	indent := fs.String("indent", "  ", "indentation string")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var doc gltf.Document
+	if err := gltf.NewDecoder(f).Decode(&doc); err != nil {
+		return err
+	}
+	// Only print the JSON chunk of GLB files, not the binary chunk as a data URI.
+	for _, b := range doc.Buffers {
+		if b.URI == "" {
+			b.Data, b.Source = nil, nil
+		}
+	}
+	enc := gltf.NewEncoder(w)
+	enc.AsBinary = false
+	enc.SetJSONIndent("", *indent)
+	return enc.Encode(&doc)
+}
+
+func runExtractImages(args []string, w io.Writer) error {
+	args, err := parseArgs(flag.NewFlagSet("extract-images", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	doc, err := gltf.Open(args[0])
+	if err != nil {
+		return err
+	}
+	dir := args[1]
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for i, im := range doc.Images {
+		if isRemote(im.URI) {
+			fmt.Fprintf(w, "image %d: skipping remote URI %s\n", i, im.URI)
+			continue
+		}
+		data, err := imageData(doc, filepath.Dir(args[0]), im)
+		if err != nil {
+			return fmt.Errorf("image %d: %w", i, err)
+		}
+		name := im.Name
+		if name == "" && im.URI != "" && !im.IsEmbeddedResource() {
+			name = path.Base(im.URI)
+		}
+		if name == "" {
+			name = "image" + strconv.Itoa(i)
+		}
+		base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		mimeType := im.MimeType
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
+		}
+		ext := imageExt(mimeType)
+		// Images with the same name get a numeric suffix instead of overwriting each other.
+		name = base + ext
+		for n := 1; used[name]; n++ {
+			name = base + "_" + strconv.Itoa(n) + ext
+		}
+		used[name] = true
+		name = filepath.Join(dir, name)
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+// imageData returns the content of im, which can be stored in a buffer view,
+// embedded in the URI or in an external file relative to dir.
+func imageData(doc *gltf.Document, dir string, im *gltf.Image) ([]byte, error) {
+	switch {
+	case im.BufferView != nil:
+		if *im.BufferView >= len(doc.BufferViews) {
+			return nil, errors.New("invalid buffer view")
+		}
+		return modeler.ReadBufferView(doc, doc.BufferViews[*im.BufferView])
+	case im.IsEmbeddedResource():
+		return im.MarshalData()
+	case im.URI == "":
+		return nil, errors.New("image without URI nor buffer view")
+	}
+	name := filepath.FromSlash(im.URI)
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("image URI %s is outside the document directory", im.URI)
+	}
+	return os.ReadFile(filepath.Join(dir, name))
+}
+
+// imageExt returns the file extension used by extract-images for mimeType.
+func imageExt(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	}
+	return ".bin"
+}
+
+func isRemote(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme != "" && u.Scheme != "data"
+}
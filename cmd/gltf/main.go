@@ -0,0 +1,87 @@
+// Command gltf inspects and converts glTF and GLB assets.
+//
+// Usage:
+//
+//	gltf <command> [arguments]
+//
+// The commands are:
+//
+//	info            print the number of objects, sizes and extensions of an asset
+//	validate        check that an asset can be decoded and its accessors read
+//	pack            convert a glTF into a self-contained GLB
+//	unpack          convert a GLB into a glTF with an external buffer
+//	pretty          print the JSON content of an asset indented
+//	extract-images  write the images of an asset to a directory
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, w io.Writer) error
+}
+
+var commands = []command{
+	{"info", "info FILE", runInfo},
+	{"validate", "validate FILE...", runValidate},
+	{"pack", "pack FILE.gltf OUT.glb", runPack},
+	{"unpack", "unpack FILE.glb OUT.gltf", runUnpack},
+	{"pretty", "pretty [-indent STRING] FILE", runPretty},
+	{"extract-images", "extract-images FILE DIR", runExtractImages},
+}
+
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	if err == nil {
+		return
+	}
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	fmt.Fprintln(os.Stderr, "gltf:", err)
+	os.Exit(1)
+}
+
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		return cmd.run(args[1:], w)
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: gltf <command> [arguments]")
+	fmt.Fprintln(w)
+	for _, cmd := range commands {
+		fmt.Fprintln(w, "  gltf", cmd.usage)
+	}
+}
+
+// parseArgs parses the flags defined in fs and returns
+// the positional arguments, which must be at least n.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() < n {
+		return nil, fmt.Errorf("%w: missing arguments for %s", errUsage, fs.Name())
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/diff"
+)
+
+const cube = "../../testdata/Cube/glTF/Cube.gltf"
+
+func TestRun_Usage(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"foo"},
+		{"info"},
+		{"pack", cube},
+		{"pretty", "-foo", cube},
+	}
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			if err := run(args, new(bytes.Buffer)); !errors.Is(err, errUsage) {
+				t.Errorf("run() error = %v, want errUsage", err)
+			}
+		})
+	}
+}
+
+func TestRun_Info(t *testing.T) {
+	var buf bytes.Buffer
+	if err := run([]string{"info", cube}, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	for _, want := range []string{"version:     2.0", "meshes:      1", "images:      2", "buffers:     1 (1800 bytes)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("run() = %s, want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := run([]string{"validate", cube}, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if err := run([]string{"validate", "../../testdata/issue63/none.gltf"}, &buf); err == nil {
+		t.Error("run() expected error")
+	}
+}
+
+func TestRun_PackUnpack(t *testing.T) {
+	dir := t.TempDir()
+	glb, gltfName := filepath.Join(dir, "cube.glb"), filepath.Join(dir, "out", "cube.gltf")
+	if err := run([]string{"pack", cube, glb}, new(bytes.Buffer)); err != nil {
+		t.Fatalf("pack error = %v", err)
+	}
+	packed, err := gltf.Open(glb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed.Buffers) != 1 || packed.Buffers[0].URI != "" {
+		t.Errorf("pack buffers = %v, want a single binary chunk", packed.Buffers)
+	}
+	for i, im := range packed.Images {
+		if im.BufferView == nil || im.URI != "" || im.MimeType != "image/png" {
+			t.Errorf("pack image %d = %+v, want stored in a buffer view", i, im)
+		}
+	}
+
+	os.Mkdir(filepath.Dir(gltfName), 0o755)
+	if err := run([]string{"unpack", glb, gltfName}, new(bytes.Buffer)); err != nil {
+		t.Fatalf("unpack error = %v", err)
+	}
+	unpacked, err := gltf.Open(gltfName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unpacked.Buffers[0].URI != "cube.bin" {
+		t.Errorf("unpack buffer URI = %s, want cube.bin", unpacked.Buffers[0].URI)
+	}
+	original, err := gltf.Open(cube)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := diff.Diff(original, unpacked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range changes {
+		if !strings.HasPrefix(c.Path, "/accessors/") && !strings.HasPrefix(c.Path, "/buffer") && !strings.HasPrefix(c.Path, "/images/") {
+			t.Errorf("unexpected change %v", c)
+		}
+	}
+}
+
+func TestRun_Pretty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := run([]string{"pretty", "-indent", "\t", cube}, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n\t\"accessors\": [") {
+		t.Errorf("run() = %s, want tab indented JSON", buf.String())
+	}
+	buf.Reset()
+	if err := run([]string{"pretty", "../../testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb"}, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "data:") || !strings.Contains(buf.String(), `"byteLength"`) {
+		t.Errorf("run() = %s, want the JSON chunk without the binary chunk", buf.String())
+	}
+}
+
+func TestRun_ExtractImages(t *testing.T) {
+	dir := t.TempDir()
+	glb := filepath.Join(dir, "cube.glb")
+	if err := run([]string{"pack", cube, glb}, new(bytes.Buffer)); err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []string{cube, glb} {
+		out := filepath.Join(dir, filepath.Ext(in)[1:])
+		var buf bytes.Buffer
+		if err := run([]string{"extract-images", in, out}, &buf); err != nil {
+			t.Fatalf("run() error = %v", err)
+		}
+		for _, name := range []string{"Cube_BaseColor.png", "Cube_MetallicRoughness.png"} {
+			got, err := os.ReadFile(filepath.Join(out, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := os.ReadFile(filepath.Join("../../testdata/Cube/glTF", name))
+			if !bytes.Equal(got, want) {
+				t.Errorf("run() %s content differs", name)
+			}
+		}
+	}
+}
+
+func TestRun_ExtractImages_NonLocal(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "secret.png"), []byte("\x89PNG\r\n\x1a\n"), 0o644)
+	in := filepath.Join(dir, "model", "in.gltf")
+	os.Mkdir(filepath.Dir(in), 0o755)
+	doc := &gltf.Document{Images: []*gltf.Image{{URI: "../secret.png"}}}
+	if err := gltf.Save(doc, in); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"extract-images", in, filepath.Join(dir, "out")}, new(bytes.Buffer)); err == nil {
+		t.Error("run() expected error reading an image outside the document directory")
+	}
+}
+
+func TestRun_ExtractImages_SameName(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.gltf")
+	doc := &gltf.Document{Images: []*gltf.Image{
+		{Name: "tex", URI: "data:image/png;base64,iVBORw0KGgoA"},
+		{Name: "tex", URI: "data:image/png;base64,iVBORw0KGgoB"},
+	}}
+	if err := gltf.Save(doc, in); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := run([]string{"extract-images", in, out}, new(bytes.Buffer)); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	for _, name := range []string{"tex.png", "tex_1.png"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("run() did not write %s: %v", name, err)
+		}
+	}
+}
@@ -415,6 +415,8 @@ const (
 	glbHeaderMagic = 0x46546c67
 	glbChunkJSON   = 0x4e4f534a
 	glbChunkBIN    = 0x004e4942
+
+	glbContentFormatJSONV1 = 0
 )
 
 type chunkHeader struct {
@@ -0,0 +1,78 @@
+package gltf
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+// An OpenContextFS is a file system whose files can be opened
+// with a context that cancels the operation.
+//
+// Decoder.DecodeContext uses OpenContext instead of Open
+// to read external resources when Decoder.Fsys implements it.
+type OpenContextFS interface {
+	fs.FS
+	OpenContext(ctx context.Context, name string) (fs.File, error)
+}
+
+// A CreateContextFS is a CreateFS whose files can be created
+// with a context that cancels the operation.
+//
+// Encoder.EncodeContext uses CreateContext instead of Create
+// to write external resources when Encoder.Fsys implements it.
+type CreateContextFS interface {
+	CreateFS
+	CreateContext(ctx context.Context, name string) (io.WriteCloser, error)
+}
+
+// readFileContext reads the named file from fsys, aborting when ctx is done.
+func readFileContext(ctx context.Context, fsys fs.FS, name string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var (
+		f   fs.File
+		err error
+	)
+	if cfs, ok := fsys.(OpenContextFS); ok {
+		f, err = cfs.OpenContext(ctx, name)
+	} else if ctx.Done() == nil {
+		// The context can't be canceled, use the fast path.
+		return fs.ReadFile(fsys, name)
+	} else {
+		f, err = fsys.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(&contextReader{ctx: ctx, r: f})
+}
+
+// createContext creates the named file in fsys.
+func createContext(ctx context.Context, fsys CreateFS, name string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cfs, ok := fsys.(CreateContextFS); ok {
+		return cfs.CreateContext(ctx, name)
+	}
+	return fsys.Create(name)
+}
+
+// contextReader is a reader that fails with the context error once ctx is done.
+// A nil ctx is never done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if c.ctx != nil {
+		if err := c.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+	return c.r.Read(p)
+}
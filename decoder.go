@@ -3,6 +3,7 @@ package gltf
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
@@ -12,7 +13,10 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Open will open a glTF or GLB file specified by name and return the Document.
@@ -34,33 +38,74 @@ func Open(name string) (*Document, error) {
 //
 // Only buffers with relative URIs will be read from Fsys.
 // Fsys is called to read external resources.
+//
+// glTF 1.0 assets, either JSON or GLB version 1 (KHR_binary_glTF),
+// are upgraded to glTF 2.0 while decoding.
+//
+// Concurrency is the maximum number of external buffers loaded concurrently.
+// Buffers are loaded sequentially if it is lower than 2,
+// otherwise Fsys must be safe for concurrent use. The reported error is
+// always the one of the first buffer, in document order, that fails to load.
+//
+// If StrictGLB is true GLB inputs are rejected unless they are version 2,
+// all the chunks are 4-byte aligned, the JSON chunk is padded with spaces,
+// the BIN chunk is padded with at most 3 zeros, the header length matches the chunks
+// and there is no data after it. The errors report the offset of the problem.
+//
+// The errors returned by Decode can be inspected with errors.As
+// to retrieve a *DecodeError containing the error position.
 type Decoder struct {
-	Fsys fs.FS
-	r    *bufio.Reader
+	Fsys        fs.FS
+	Concurrency int
+	StrictGLB   bool
+	r           *bufio.Reader
+	cr          *contextReader
+	content     *jsonContent
+	mapped      []byte        // Whole input, if it is memory-mapped.
+	mr          *bytes.Reader // Reader of mapped.
 }
 
 // NewDecoder returns a new decoder that reads from r.
 func NewDecoder(r io.Reader) *Decoder {
+	cr := &contextReader{r: r}
 	return &Decoder{
-		r: bufio.NewReader(r),
+		r:  bufio.NewReader(cr),
+		cr: cr,
 	}
 }
 
 // NewDecoder returns a new decoder that reads from r.
 func NewDecoderFS(r io.Reader, fsys fs.FS) *Decoder {
-	return &Decoder{
-		Fsys: fsys,
-		r:    bufio.NewReader(r),
-	}
+	d := NewDecoder(r)
+	d.Fsys = fsys
+	return d
 }
 
 // Decode reads the next JSON-encoded value from its
 // input and stores it in the value pointed to by doc.
 func (d *Decoder) Decode(doc *Document) error {
-	isBinary, err := d.decodeDocument(doc)
+	return d.DecodeContext(context.Background(), doc)
+}
+
+// DecodeContext is like Decode but aborts reading the input
+// and loading the external buffers when ctx is done,
+// in which case the returned error wraps ctx.Err().
+//
+// If Fsys implements OpenContextFS, ctx is passed to OpenContext.
+func (d *Decoder) DecodeContext(ctx context.Context, doc *Document) error {
+	d.cr.ctx = ctx
+	d.content = new(jsonContent)
+	defer func() {
+		d.cr.ctx = nil
+		d.content = nil
+	}()
+	glbHeader, err := d.decodeDocument(doc)
 	if err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	for _, b := range doc.Buffers {
 		if !b.IsEmbeddedResource() {
@@ -78,39 +123,212 @@ func (d *Decoder) Decode(doc *Document) error {
 	}
 
 	var externalBufferIndex = 0
-	if isBinary && len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
-		externalBufferIndex = 1
-		if err := d.decodeBinaryBuffer(doc.Buffers[0]); err != nil {
+	if glbHeader != nil {
+		if len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
+			externalBufferIndex = 1
+		}
+		if err := d.decodeGLBChunks(doc, glbHeader); err != nil {
 			return err
 		}
 	}
-	for i := externalBufferIndex; i < len(doc.Buffers); i++ {
-		if err := d.decodeBuffer(doc.Buffers[i]); err != nil {
+	return d.decodeBuffers(ctx, doc, externalBufferIndex)
+}
+
+// decodeGLBChunks reads the GLB chunks that follow the JSON chunk.
+func (d *Decoder) decodeGLBChunks(doc *Document, glbHeader *glbHeader) error {
+	offset := binary.Size(glbHeader) + int(glbHeader.JSONHeader.Length)
+	if len(doc.Buffers) > 0 && doc.Buffers[0].URI == "" {
+		var (
+			binHeader *chunkHeader
+			err       error
+		)
+		if glbHeader.Version == 1 {
+			err = d.decodeBinaryBufferV1(doc.Buffers[0])
+		} else {
+			binHeader, err = d.decodeBinaryBuffer(doc.Buffers[0])
+		}
+		if err != nil {
+			derr := d.content.errorAtPointer(err, "/buffers/0")
+			derr.Offset = int64(offset)
+			return derr
+		}
+		if binHeader == nil {
+			return nil
+		}
+		if d.StrictGLB && binHeader.Length%4 != 0 {
+			return &DecodeError{Offset: int64(offset), Err: errors.New("gltf: GLB BIN chunk is not 4-byte aligned")}
+		}
+		offset += binary.Size(binHeader) + doc.Buffers[0].ByteLength
+		n, err := d.decodeBinaryPadding(int(binHeader.Length)-doc.Buffers[0].ByteLength, offset)
+		if err != nil {
 			return err
 		}
+		offset += n
+	}
+	if glbHeader.Version != 2 {
+		return nil
+	}
+	if d.StrictGLB && offset > int(glbHeader.Length) {
+		return &DecodeError{Offset: 8, Err: errors.New("gltf: GLB length is smaller than its chunks")}
+	}
+	var err error
+	doc.Chunks, err = d.decodeChunks(int(glbHeader.Length)-offset, offset)
+	if err != nil || !d.StrictGLB {
+		return err
+	}
+	if _, err := d.r.Peek(1); err == nil {
+		return &DecodeError{Offset: int64(glbHeader.Length), Err: errors.New("gltf: unexpected data after the GLB length")}
+	} else if err != io.EOF {
+		return err
 	}
 	return nil
 }
 
-func (d *Decoder) decodeDocument(doc *Document) (bool, error) {
+// decodeBinaryPadding consumes the n padding bytes of the BIN chunk located at offset.
+// If the decoder is strict they must be at most 3 zeros.
+func (d *Decoder) decodeBinaryPadding(n, offset int) (int, error) {
+	if !d.StrictGLB {
+		n, _ := d.r.Discard(n)
+		return n, nil
+	}
+	if n > 3 {
+		return 0, &DecodeError{Offset: int64(offset), Err: fmt.Errorf("gltf: GLB BIN chunk has %d padding bytes, at most 3 are allowed", n)}
+	}
+	for i := 0; i < n; i++ {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return i, &DecodeError{Offset: int64(offset + i), Err: io.ErrUnexpectedEOF}
+		}
+		if c != 0 {
+			return i, &DecodeError{Offset: int64(offset + i), Err: fmt.Errorf("gltf: invalid GLB padding byte 0x%02x", c)}
+		}
+	}
+	return n, nil
+}
+
+// decodeBuffers loads the buffers starting at index start,
+// using up to d.Concurrency goroutines.
+func (d *Decoder) decodeBuffers(ctx context.Context, doc *Document, start int) error {
+	workers := d.Concurrency
+	if n := len(doc.Buffers) - start; n < workers {
+		workers = n
+	}
+	if workers < 2 {
+		for i := start; i < len(doc.Buffers); i++ {
+			if err := d.decodeBuffer(ctx, doc.Buffers[i]); err != nil {
+				return d.content.errorAtPointer(err, "/buffers/"+strconv.Itoa(i))
+			}
+		}
+		return nil
+	}
+	errs := make([]error, len(doc.Buffers))
+	// failed is the lowest index of the buffers that failed to load.
+	// Buffers after it are not loaded, as it is the error to be reported,
+	// but the ones before it are, as they could fail too.
+	var failed atomic.Int64
+	failed.Store(int64(len(doc.Buffers)))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if int64(i) > failed.Load() {
+					continue
+				}
+				if err := d.decodeBuffer(ctx, doc.Buffers[i]); err != nil {
+					errs[i] = err
+					for old := failed.Load(); int64(i) < old && !failed.CompareAndSwap(old, int64(i)); {
+						old = failed.Load()
+					}
+				}
+			}
+		}()
+	}
+	for i := start; i < len(doc.Buffers) && int64(i) < failed.Load(); i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return d.content.errorAtPointer(err, "/buffers/"+strconv.Itoa(i))
+		}
+	}
+	return nil
+}
+
+// decodeDocument decodes the JSON content into doc,
+// upgrading it to glTF 2.0 if it is a glTF 1.0 asset.
+// The returned header is nil if the input is not a GLB.
+func (d *Decoder) decodeDocument(doc *Document) (*glbHeader, error) {
 	glbHeader, err := d.readGLBHeader()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	var (
-		jd       *json.Decoder
-		isBinary bool
-	)
+	var lr *io.LimitedReader
+	d.content.lines.r = d.r
 	if glbHeader != nil {
-		jd = json.NewDecoder(&io.LimitedReader{R: d.r, N: int64(glbHeader.JSONHeader.Length)})
-		isBinary = true
-	} else {
-		jd = json.NewDecoder(d.r)
-		isBinary = false
+		d.content.start = int64(binary.Size(glbHeader))
+		lr = &io.LimitedReader{R: d.r, N: int64(glbHeader.JSONHeader.Length)}
+		d.content.lines.r = lr
 	}
 
-	err = jd.Decode(doc)
-	return isBinary, err
+	var raw json.RawMessage
+	jd := json.NewDecoder(&d.content.lines)
+	err = jd.Decode(&raw)
+	if lr != nil {
+		if err == nil && d.StrictGLB {
+			err = d.decodeJSONPadding(jd, lr)
+		}
+		// Discard the JSON chunk padding.
+		io.Copy(io.Discard, lr)
+	}
+	if err != nil {
+		var derr *DecodeError
+		if errors.As(err, &derr) {
+			return glbHeader, err
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return glbHeader, d.content.jsonError(err, nil)
+		}
+		return glbHeader, &DecodeError{Offset: d.content.start + jd.InputOffset(), Err: err}
+	}
+	d.content.data = raw
+	d.content.dataOffset = jd.InputOffset() - int64(len(raw))
+	// target is the value the content is decoded into, nil if it is upgraded.
+	var target any = doc
+	if glbHeader != nil && glbHeader.Version == 1 {
+		target, err = nil, upgradeV1(raw, doc)
+	} else if err = json.Unmarshal(raw, doc); err != nil || isVersion1(doc.Asset.Version) {
+		// glTF 1.0 documents usually fail to decode as 2.0,
+		// so the version is only checked again in that case.
+		if isVersion1(peekVersion(raw)) {
+			target, err = nil, upgradeV1(raw, doc)
+		}
+	}
+	if err != nil {
+		return glbHeader, d.content.jsonError(err, target)
+	}
+	return glbHeader, nil
+}
+
+// decodeJSONPadding checks that the JSON chunk content remaining after
+// the JSON value, either buffered by jd or unread in lr, is made of spaces.
+func (d *Decoder) decodeJSONPadding(jd *json.Decoder, lr *io.LimitedReader) error {
+	offset := d.content.start + jd.InputOffset()
+	rest, err := io.ReadAll(io.MultiReader(jd.Buffered(), lr))
+	if err != nil {
+		return &DecodeError{Offset: offset, Err: err}
+	}
+	for i, c := range rest {
+		if c != ' ' {
+			return &DecodeError{Offset: offset + int64(i), Err: fmt.Errorf("gltf: invalid GLB padding byte 0x%02x", c)}
+		}
+	}
+	return nil
 }
 
 func (d *Decoder) readGLBHeader() (*glbHeader, error) {
@@ -125,11 +343,28 @@ func (d *Decoder) readGLBHeader() (*glbHeader, error) {
 		return nil, nil
 	}
 	d.r.Read(chunk)
-	return &header, d.validateGLBHeader(&header)
+	if d.StrictGLB && header.Version != 2 {
+		// Offset of the version field.
+		return nil, &DecodeError{Offset: 4, Err: fmt.Errorf("gltf: unsupported GLB version %d", header.Version)}
+	}
+	if err := d.validateGLBHeader(&header); err != nil {
+		// Offset of the JSON chunk header.
+		return nil, &DecodeError{Offset: 12, Err: err}
+	}
+	if d.StrictGLB && header.JSONHeader.Length%4 != 0 {
+		return nil, &DecodeError{Offset: 12, Err: errors.New("gltf: GLB JSON chunk is not 4-byte aligned")}
+	}
+	return &header, nil
 }
 
 func (d *Decoder) validateGLBHeader(header *glbHeader) error {
-	if header.JSONHeader.Type != glbChunkJSON || (header.JSONHeader.Length+uint32(binary.Size(header))) > header.Length {
+	// GLB version 1 (KHR_binary_glTF) defines the content format instead of a chunk type,
+	// being 0 the only valid value.
+	jsonType := uint32(glbChunkJSON)
+	if header.Version == 1 {
+		jsonType = glbContentFormatJSONV1
+	}
+	if header.JSONHeader.Type != jsonType || (header.JSONHeader.Length+uint32(binary.Size(header))) > header.Length {
 		return errors.New("gltf: Invalid GLB JSON header")
 	}
 	return nil
@@ -143,7 +378,7 @@ func (d *Decoder) chunkHeader() (*chunkHeader, error) {
 	return &header, nil
 }
 
-func (d *Decoder) decodeBuffer(buffer *Buffer) error {
+func (d *Decoder) decodeBuffer(ctx context.Context, buffer *Buffer) error {
 	if err := d.validateBuffer(buffer); err != nil {
 		return err
 	}
@@ -156,7 +391,7 @@ func (d *Decoder) decodeBuffer(buffer *Buffer) error {
 	} else {
 		err = validateBufferURI(buffer.URI)
 		if err == nil && d.Fsys != nil {
-			buffer.Data, err = fs.ReadFile(d.Fsys, buffer.URI)
+			buffer.Data, err = readFileContext(ctx, d.Fsys, buffer.URI)
 			if len(buffer.Data) > int(buffer.ByteLength) {
 				buffer.Data = buffer.Data[:buffer.ByteLength:buffer.ByteLength]
 			}
@@ -168,22 +403,119 @@ func (d *Decoder) decodeBuffer(buffer *Buffer) error {
 	return err
 }
 
-func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) error {
+// decodeBinaryBuffer reads the BIN chunk header and the buffer data,
+// but not the chunk padding.
+func (d *Decoder) decodeBinaryBuffer(buffer *Buffer) (*chunkHeader, error) {
 	if err := d.validateBuffer(buffer); err != nil {
-		return err
+		return nil, err
 	}
 	header, err := d.chunkHeader()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if header.Type != glbChunkBIN || header.Length < uint32(buffer.ByteLength) {
-		return errors.New("gltf: Invalid GLB BIN header")
+		return nil, errors.New("gltf: Invalid GLB BIN header")
+	}
+	buffer.Data, err = d.readData(buffer.ByteLength)
+	if err != nil {
+		return nil, err
 	}
-	buffer.Data = make([]byte, buffer.ByteLength)
-	_, err = io.ReadFull(d.r, buffer.Data)
+	return header, nil
+}
+
+// decodeChunks reads the chunks found in the next n bytes of the input, located at offset.
+// Unknown chunks must be ignored by loaders, so malformed ones are silently discarded
+// unless the decoder is strict.
+func (d *Decoder) decodeChunks(n, offset int) ([]Chunk, error) {
+	var chunks []Chunk
+	for n > 0 {
+		var header *chunkHeader
+		var data []byte
+		err := errors.New("gltf: GLB chunk exceeds the GLB length")
+		if n >= binary.Size(chunkHeader{}) {
+			header, err = d.chunkHeader()
+		}
+		if err == nil {
+			if int(header.Length) > n-binary.Size(header) {
+				err = errors.New("gltf: GLB chunk exceeds the GLB length")
+			} else if d.StrictGLB && header.Length%4 != 0 {
+				err = errors.New("gltf: GLB chunk is not 4-byte aligned")
+			} else if d.StrictGLB && (header.Type == glbChunkJSON || header.Type == glbChunkBIN) {
+				err = errors.New("gltf: unexpected GLB JSON or BIN chunk")
+			} else {
+				data, err = d.readChunkData(int(header.Length))
+			}
+		}
+		if err != nil {
+			if d.StrictGLB {
+				return nil, &DecodeError{Offset: int64(offset), Err: err}
+			}
+			break
+		}
+		n -= binary.Size(header) + len(data)
+		offset += binary.Size(header) + len(data)
+		if header.Type == glbChunkJSON || header.Type == glbChunkBIN {
+			// Only the first JSON and BIN chunks are meaningful.
+			continue
+		}
+		if d.mapped != nil {
+			// Chunks are copied so they remain valid after the mapping is closed.
+			data = bytes.Clone(data)
+		}
+		chunks = append(chunks, Chunk{Type: header.Type, Data: data})
+	}
+	return chunks, nil
+}
+
+// decodeBinaryBufferV1 reads the body of a GLB version 1,
+// which is not preceded by a chunk header.
+func (d *Decoder) decodeBinaryBufferV1(buffer *Buffer) error {
+	if err := d.validateBuffer(buffer); err != nil {
+		return err
+	}
+	var err error
+	buffer.Data, err = d.readData(buffer.ByteLength)
 	return err
 }
 
+// readChunkData is like readData but allocates the memory as the data is read,
+// as the chunk length has only been checked against the GLB length,
+// so a corrupted length does not allocate more than the remaining input.
+func (d *Decoder) readChunkData(n int) ([]byte, error) {
+	if d.mapped != nil {
+		return d.readData(n)
+	}
+	data, err := io.ReadAll(io.LimitReader(d.r, int64(n)))
+	if err == nil && len(data) < n {
+		err = io.ErrUnexpectedEOF
+	}
+	return data, err
+}
+
+// readData reads the next n bytes of the input.
+// If the input is memory-mapped the returned slice points into the mapping.
+func (d *Decoder) readData(n int) ([]byte, error) {
+	if d.mapped == nil {
+		data := make([]byte, n)
+		_, err := io.ReadFull(d.r, data)
+		return data, err
+	}
+	buffered := d.r.Buffered()
+	offset := len(d.mapped) - d.mr.Len() - buffered
+	if n > len(d.mapped)-offset {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if n <= buffered {
+		d.r.Discard(n)
+	} else {
+		// Skip the data in the mapping instead of reading it through d.r,
+		// which would copy it and fault in every page.
+		d.mr.Seek(int64(n-buffered), io.SeekCurrent)
+		d.r.Reset(d.cr)
+	}
+	return d.mapped[offset : offset+n : offset+n], nil
+}
+
 func (d *Decoder) validateBuffer(buffer *Buffer) error {
 	if buffer.ByteLength == 0 {
 		return errors.New("gltf: Invalid buffer.byteLength value = 0")
@@ -2,11 +2,22 @@ package gltf
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"testing/fstest"
+	"testing/iotest"
+	"time"
 
 	"github.com/go-test/deep"
 )
@@ -170,6 +181,315 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+type mockOpenContextFS struct {
+	fstest.MapFS
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (m *mockOpenContextFS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	m.ctx = ctx
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return m.Open(name)
+}
+
+func TestDecoder_DecodeContext(t *testing.T) {
+	const content = `{"buffers": [{"byteLength": 1, "uri": "a.bin"}, {"byteLength": 1, "uri": "b.bin"}]}`
+	files := fstest.MapFS{"a.bin": {Data: []byte{1}}, "b.bin": {Data: []byte{2}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := NewDecoderFS(bytes.NewBufferString(content), files).DecodeContext(ctx, new(Document))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Decoder.DecodeContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	// Canceled while loading the first buffer.
+	ctx, cancel = context.WithCancel(context.Background())
+	m := &mockOpenContextFS{MapFS: files, cancel: cancel}
+	err = NewDecoderFS(bytes.NewBufferString(content), m).DecodeContext(ctx, new(Document))
+	var derr *DecodeError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &derr) || derr.Pointer != "/buffers/0" {
+		t.Errorf("Decoder.DecodeContext() error = %v, want %v at /buffers/0", err, context.Canceled)
+	}
+
+	m = &mockOpenContextFS{MapFS: files}
+	ctx = context.WithValue(context.Background(), struct{}{}, 1)
+	doc := new(Document)
+	if err := NewDecoderFS(bytes.NewBufferString(content), m).DecodeContext(ctx, doc); err != nil {
+		t.Fatalf("Decoder.DecodeContext() error = %v", err)
+	}
+	if m.ctx != ctx {
+		t.Error("Decoder.DecodeContext() did not pass the context to OpenContext")
+	}
+	if !bytes.Equal(doc.Buffers[1].Data, []byte{2}) {
+		t.Errorf("Decoder.DecodeContext() buffer = %v, want [2]", doc.Buffers[1].Data)
+	}
+}
+
+// mockConcurrentFS records the maximum number of files open at the same time.
+type mockConcurrentFS struct {
+	fstest.MapFS
+	mu        sync.Mutex
+	open, max int
+}
+
+func (m *mockConcurrentFS) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	m.open++
+	if m.open > m.max {
+		m.max = m.open
+	}
+	m.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	m.mu.Lock()
+	m.open--
+	m.mu.Unlock()
+	return m.MapFS.Open(name)
+}
+
+func TestDecoder_Decode_Concurrency(t *testing.T) {
+	var content strings.Builder
+	files := fstest.MapFS{}
+	content.WriteString(`{"buffers": [`)
+	for i := 0; i < 20; i++ {
+		if i > 0 {
+			content.WriteString(",")
+		}
+		name := fmt.Sprintf("%d.bin", i)
+		fmt.Fprintf(&content, `{"byteLength": 1, "uri": %q}`, name)
+		if i != 7 && i != 15 {
+			files[name] = &fstest.MapFile{Data: []byte{byte(i)}}
+		}
+	}
+	content.WriteString(`]}`)
+
+	m := &mockConcurrentFS{MapFS: files}
+	for i := 0; i < 10; i++ {
+		d := NewDecoderFS(strings.NewReader(content.String()), m)
+		d.Concurrency = 4
+		doc := new(Document)
+		err := d.Decode(doc)
+		var derr *DecodeError
+		if !errors.As(err, &derr) || derr.Pointer != "/buffers/7" || !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("Decoder.Decode() error = %v, want not exist at /buffers/7", err)
+		}
+		for j := 0; j < 7; j++ {
+			if !bytes.Equal(doc.Buffers[j].Data, []byte{byte(j)}) {
+				t.Errorf("Decoder.Decode() buffer %d = %v, want [%d]", j, doc.Buffers[j].Data, j)
+			}
+		}
+	}
+	if m.max > 4 {
+		t.Errorf("Decoder.Decode() loaded %d buffers concurrently, want at most 4", m.max)
+	}
+
+	m.MapFS["7.bin"] = &fstest.MapFile{Data: []byte{7}}
+	m.MapFS["15.bin"] = &fstest.MapFile{Data: []byte{15}}
+	d := NewDecoderFS(strings.NewReader(content.String()), m)
+	d.Concurrency = 4
+	doc := new(Document)
+	if err := d.Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	for j, b := range doc.Buffers {
+		if !bytes.Equal(b.Data, []byte{byte(j)}) {
+			t.Errorf("Decoder.Decode() buffer %d = %v, want [%d]", j, b.Data, j)
+		}
+	}
+}
+
+func TestDecoder_Decode_Chunks(t *testing.T) {
+	glb := func(chunks ...[]byte) []byte {
+		content := `{"buffers": [{"byteLength": 3}]}`
+		var body bytes.Buffer
+		body.WriteString(content)
+		for _, c := range chunks {
+			body.Write(c)
+		}
+		var buf bytes.Buffer
+		header := glbHeader{
+			Magic:      glbHeaderMagic,
+			Version:    2,
+			Length:     uint32(12 + 8 + body.Len()),
+			JSONHeader: chunkHeader{Length: uint32(len(content)), Type: glbChunkJSON},
+		}
+		binary.Write(&buf, binary.LittleEndian, &header)
+		buf.Write(body.Bytes())
+		return buf.Bytes()
+	}
+	bin := []byte{4, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, 0}
+	meta := []byte{4, 0, 0, 0, 'M', 'E', 'T', 'A', 'a', 'b', 'c', 'd'}
+	tests := []struct {
+		name string
+		data []byte
+		want []Chunk
+	}{
+		{"none", glb(bin), nil},
+		{"one", glb(bin, meta), []Chunk{{Type: 0x4154454d, Data: []byte("abcd")}}},
+		{"duplicatedBin", glb(bin, bin, meta), []Chunk{{Type: 0x4154454d, Data: []byte("abcd")}}},
+		{"truncated", glb(bin, meta, meta[:10]), []Chunk{{Type: 0x4154454d, Data: []byte("abcd")}}},
+		{"trailing", append(glb(bin, meta), meta...), []Chunk{{Type: 0x4154454d, Data: []byte("abcd")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, d := range []*Decoder{NewDecoder(bytes.NewReader(tt.data)), newMappedDecoder(tt.data, nil)} {
+				doc := new(Document)
+				if err := d.Decode(doc); err != nil {
+					t.Fatalf("Decoder.Decode() error = %v", err)
+				}
+				if diff := deep.Equal(doc.Chunks, tt.want); diff != nil {
+					t.Errorf("Decoder.Decode() chunks = %v", diff)
+				}
+				if !bytes.Equal(doc.Buffers[0].Data, []byte{1, 2, 3}) {
+					t.Errorf("Decoder.Decode() buffer = %v", doc.Buffers[0].Data)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_ChunkLength(t *testing.T) {
+	content := `{"buffers": [{"byteLength": 3}]}`
+	var buf bytes.Buffer
+	header := glbHeader{
+		Magic:      glbHeaderMagic,
+		Version:    2,
+		Length:     1 << 30,
+		JSONHeader: chunkHeader{Length: uint32(len(content)), Type: glbChunkJSON},
+	}
+	binary.Write(&buf, binary.LittleEndian, &header)
+	buf.WriteString(content)
+	buf.Write([]byte{4, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, 0})
+	buf.Write([]byte{0, 0, 0, 0x3f, 'M', 'E', 'T', 'A', 'a', 'b', 'c', 'd'})
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("Decoder.Decode() allocated %d bytes for a truncated chunk", n)
+	}
+	if len(doc.Chunks) != 0 {
+		t.Errorf("Decoder.Decode() chunks = %v, want none", doc.Chunks)
+	}
+}
+
+func TestDecoder_Decode_StrictGLB(t *testing.T) {
+	type glb struct {
+		version uint32
+		length  int // Zero means the actual length.
+		json    string
+		chunks  [][]byte
+		trail   []byte
+	}
+	encode := func(g glb) []byte {
+		var body bytes.Buffer
+		for _, c := range g.chunks {
+			body.Write(c)
+		}
+		header := glbHeader{
+			Magic:      glbHeaderMagic,
+			Version:    g.version,
+			Length:     uint32(12 + 8 + len(g.json) + body.Len()),
+			JSONHeader: chunkHeader{Length: uint32(len(g.json)), Type: glbChunkJSON},
+		}
+		if g.length != 0 {
+			header.Length = uint32(g.length)
+		}
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, &header)
+		buf.WriteString(g.json)
+		buf.Write(body.Bytes())
+		buf.Write(g.trail)
+		return buf.Bytes()
+	}
+	const content = `{"buffers":[{"byteLength":3}]}  ` // 32 bytes.
+	bin := []byte{4, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, 0}
+	meta := []byte{4, 0, 0, 0, 'M', 'E', 'T', 'A', 'a', 'b', 'c', 'd'}
+	tests := []struct {
+		name       string
+		g          glb
+		wantOffset int64
+	}{
+		{"valid", glb{version: 2, json: content, chunks: [][]byte{bin, meta}}, -1},
+		{"validNoBin", glb{version: 2, json: `{"asset":{"version":"2.0"}}     `, chunks: [][]byte{meta}}, -1},
+		{"version", glb{version: 1, json: content}, 4},
+		{"jsonAlignment", glb{version: 2, json: content[:31], chunks: [][]byte{bin}}, 12},
+		{"jsonPaddingNewline", glb{version: 2, json: content[:30] + " \n", chunks: [][]byte{bin}}, 51},
+		{"jsonPadding", glb{version: 2, json: content[:30] + "\x00 ", chunks: [][]byte{bin}}, 50},
+		{"binAlignment", glb{version: 2, json: content, chunks: [][]byte{{3, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3}}}, 52},
+		{"binPadding", glb{version: 2, json: content, chunks: [][]byte{{4, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, ' '}}}, 63},
+		{"binPaddingLong", glb{version: 2, json: content, chunks: [][]byte{{8, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, 0, 0, 0, 0, 0}}}, 63},
+		{"lengthSmall", glb{version: 2, length: 60, json: content, chunks: [][]byte{bin}}, 8},
+		{"lengthBig", glb{version: 2, length: 80, json: content, chunks: [][]byte{bin}}, 64},
+		{"chunkAlignment", glb{version: 2, json: content, chunks: [][]byte{bin, {3, 0, 0, 0, 'M', 'E', 'T', 'A', 1, 2, 3, 0}}}, 64},
+		{"chunkTruncated", glb{version: 2, json: content, chunks: [][]byte{bin, meta[:10]}}, 64},
+		{"duplicatedBin", glb{version: 2, json: content, chunks: [][]byte{bin, bin}}, 64},
+		{"trailing", glb{version: 2, json: content, chunks: [][]byte{bin}, trail: []byte{0}}, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := encode(tt.g)
+			for _, d := range []*Decoder{NewDecoder(bytes.NewReader(data)), newMappedDecoder(data, nil)} {
+				d.StrictGLB = true
+				err := d.Decode(new(Document))
+				if tt.wantOffset < 0 {
+					if err != nil {
+						t.Errorf("Decoder.Decode() error = %v", err)
+					}
+					continue
+				}
+				var derr *DecodeError
+				if !errors.As(err, &derr) {
+					t.Fatalf("Decoder.Decode() error = %v, want *DecodeError", err)
+				}
+				if derr.Offset != tt.wantOffset {
+					t.Errorf("Decoder.Decode() error = %v, want offset %d", err, tt.wantOffset)
+				}
+			}
+			// Non-strict decoding is lenient with the padding, alignment and trailing data.
+			if tt.name != "version" {
+				if err := NewDecoder(bytes.NewReader(data)).Decode(new(Document)); err != nil {
+					t.Errorf("Decoder.Decode() non-strict error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_StrictGLB_Encoded(t *testing.T) {
+	doc := &Document{
+		Buffers: []*Buffer{{ByteLength: 5, Data: []byte{1, 2, 3, 4, 5}}},
+		Chunks:  []Chunk{{Type: 0x41544144, Data: []byte{1}}},
+	}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(doc); err != nil {
+		t.Fatal(err)
+	}
+	// Errors reading past the GLB length are not reported as trailing data.
+	errRead := errors.New("read error")
+	d := NewDecoder(io.MultiReader(bytes.NewReader(buf.Bytes()), iotest.ErrReader(errRead)))
+	d.StrictGLB = true
+	if err := d.Decode(new(Document)); err != errRead {
+		t.Errorf("Decoder.Decode() error = %v, want %v", err, errRead)
+	}
+	d = NewDecoder(&buf)
+	d.StrictGLB = true
+	if err := d.Decode(new(Document)); err != nil {
+		t.Errorf("Decoder.Decode() error = %v", err)
+	}
+	d = NewDecoder(bytes.NewReader(readFile("testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb")))
+	d.StrictGLB = true
+	if err := d.Decode(new(Document)); err != nil {
+		t.Errorf("Decoder.Decode() error = %v", err)
+	}
+}
+
 func TestDecoder_decodeBuffer(t *testing.T) {
 	type args struct {
 		buffer *Buffer
@@ -190,7 +510,7 @@ func TestDecoder_decodeBuffer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.d.decodeBuffer(tt.args.buffer); (err != nil) != tt.wantErr {
+			if err := tt.d.decodeBuffer(context.Background(), tt.args.buffer); (err != nil) != tt.wantErr {
 				t.Errorf("Decoder.decodeBuffer() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -224,7 +544,7 @@ func TestDecoder_decodeBinaryBuffer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.d.decodeBinaryBuffer(tt.args.buffer); (err != nil) != tt.wantErr {
+			if _, err := tt.d.decodeBinaryBuffer(tt.args.buffer); (err != nil) != tt.wantErr {
 				t.Errorf("Decoder.decodeBinaryBuffer() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
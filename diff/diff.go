@@ -0,0 +1,248 @@
+// Package diff reports the structural differences between two glTF documents.
+//
+// Objects and properties are compared by their JSON representation
+// and identified by their JSON pointer (RFC 6901), such as "/nodes/2/translation".
+// Accessor contents are compared numerically, element by element,
+// instead of comparing the raw bytes of the buffers. Changes in the buffer layout
+// are reported as changes of the accessor, buffer view and buffer properties,
+// such as "/bufferViews/0/byteOffset", but not as changes of the accessor contents
+// if the data is the same.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+// DefaultTolerance is the tolerance used by Diff.
+const DefaultTolerance = 1e-6
+
+// ChangeKind defines the kind of a change.
+type ChangeKind uint8
+
+const (
+	// Added is used when a value only exists in the second document.
+	Added ChangeKind = iota
+	// Removed is used when a value only exists in the first document.
+	Removed
+	// Modified is used when a value exists in both documents but is different.
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// A Change describes a difference between two documents.
+type Change struct {
+	Kind ChangeKind
+	// Path is the JSON pointer of the value that changed.
+	// Changes in the accessor contents are reported with the accessor path
+	// followed by "/data", such as "/accessors/0/data".
+	Path string
+	// From and To are the values in the first and the second document.
+	// From is nil for added values and To is nil for removed values.
+	// JSON values are represented as decoded by encoding/json into an any value,
+	// and accessor contents as returned by modeler.ReadAccessor.
+	From, To any
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %v", c.Kind, c.Path, c.To)
+	case Removed:
+		return fmt.Sprintf("%s %s: %v", c.Kind, c.Path, c.From)
+	}
+	return fmt.Sprintf("%s %s: %v -> %v", c.Kind, c.Path, c.From, c.To)
+}
+
+// Diff returns the changes needed to transform a into b
+// using DefaultTolerance to compare numbers.
+func Diff(a, b *gltf.Document) ([]Change, error) {
+	d := Differ{Tolerance: DefaultTolerance}
+	return d.Diff(a, b)
+}
+
+// A Differ compares glTF documents.
+type Differ struct {
+	// Tolerance is the maximum absolute difference between two numbers,
+	// either JSON numbers or accessor elements, for them to be considered equal.
+	Tolerance float64
+}
+
+// Diff returns the changes needed to transform a into b.
+// Changes are sorted by their position in the document.
+//
+// The buffers data must be loaded in order to compare accessor contents.
+// An error is returned if an accessor present in both documents cannot be read.
+func (d *Differ) Diff(a, b *gltf.Document) ([]Change, error) {
+	va, err := jsonValue(a)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := jsonValue(b)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	d.diffValue("", va, vb, &changes)
+	for i := 0; i < len(a.Accessors) && i < len(b.Accessors); i++ {
+		c, err := d.diffAccessor(a, b, i)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			changes = append(changes, *c)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return comparePaths(changes[i].Path, changes[j].Path) < 0
+	})
+	return changes, nil
+}
+
+func jsonValue(doc *gltf.Document) (any, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
+
+func (d *Differ) diffValue(path string, a, b any, changes *[]Change) {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			d.diffObject(path, a, b, changes)
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			d.diffArray(path, a, b, changes)
+			return
+		}
+	case float64:
+		if b, ok := b.(float64); ok && d.equalNumber(a, b) {
+			return
+		}
+	default:
+		if a == b {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Kind: Modified, Path: path, From: a, To: b})
+}
+
+func (d *Differ) diffObject(path string, a, b map[string]any, changes *[]Change) {
+	for k, va := range a {
+		p := path + "/" + escapePointer(k)
+		if vb, ok := b[k]; ok {
+			d.diffValue(p, va, vb, changes)
+		} else {
+			*changes = append(*changes, Change{Kind: Removed, Path: p, From: va})
+		}
+	}
+	for k, vb := range b {
+		if _, ok := a[k]; !ok {
+			*changes = append(*changes, Change{Kind: Added, Path: path + "/" + escapePointer(k), To: vb})
+		}
+	}
+}
+
+func (d *Differ) diffArray(path string, a, b []any, changes *[]Change) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		p := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(a):
+			*changes = append(*changes, Change{Kind: Added, Path: p, To: b[i]})
+		case i >= len(b):
+			*changes = append(*changes, Change{Kind: Removed, Path: p, From: a[i]})
+		default:
+			d.diffValue(p, a[i], b[i], changes)
+		}
+	}
+}
+
+func (d *Differ) diffAccessor(a, b *gltf.Document, i int) (*Change, error) {
+	da, err := modeler.ReadAccessor(a, a.Accessors[i], nil)
+	if err != nil {
+		return nil, fmt.Errorf("diff: cannot read accessor %d of the first document: %w", i, err)
+	}
+	db, err := modeler.ReadAccessor(b, b.Accessors[i], nil)
+	if err != nil {
+		return nil, fmt.Errorf("diff: cannot read accessor %d of the second document: %w", i, err)
+	}
+	fa, fb := flatten(reflect.ValueOf(da), nil), flatten(reflect.ValueOf(db), nil)
+	equal := len(fa) == len(fb)
+	for j := 0; equal && j < len(fa); j++ {
+		equal = d.equalNumber(fa[j], fb[j])
+	}
+	if equal {
+		return nil, nil
+	}
+	return &Change{Kind: Modified, Path: "/accessors/" + strconv.Itoa(i) + "/data", From: da, To: db}, nil
+}
+
+func (d *Differ) equalNumber(a, b float64) bool {
+	return a == b || math.Abs(a-b) <= d.Tolerance
+}
+
+// flatten appends all the numbers contained in v to dst.
+func flatten(v reflect.Value, dst []float64) []float64 {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dst = flatten(v.Index(i), dst)
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst = append(dst, float64(v.Int()))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dst = append(dst, float64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		dst = append(dst, v.Float())
+	}
+	return dst
+}
+
+// escapePointer escapes a JSON pointer reference token.
+func escapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+// comparePaths orders JSON pointers token by token,
+// comparing array indices numerically.
+func comparePaths(a, b string) int {
+	ta, tb := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if ta[i] == tb[i] {
+			continue
+		}
+		na, erra := strconv.Atoi(ta[i])
+		nb, errb := strconv.Atoi(tb[i])
+		if erra == nil && errb == nil {
+			return na - nb
+		}
+		return strings.Compare(ta[i], tb[i])
+	}
+	return len(ta) - len(tb)
+}
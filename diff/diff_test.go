@@ -0,0 +1,107 @@
+package diff_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/diff"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func newDocument(positions [][3]float32) *gltf.Document {
+	doc := gltf.NewDocument()
+	modeler.WritePosition(doc, positions)
+	doc.Nodes = []*gltf.Node{{Name: "a"}}
+	return doc
+}
+
+func TestDiff(t *testing.T) {
+	positions := [][3]float32{{1, 2, 3}, {4, 5, 6}}
+	tests := []struct {
+		name string
+		a, b func() *gltf.Document
+		want []diff.Change
+	}{
+		{"equal", func() *gltf.Document { return newDocument(positions) }, func() *gltf.Document { return newDocument(positions) }, nil},
+		{"tolerance", func() *gltf.Document { return newDocument([][3]float32{{0, 2, 3}, {4, 5, 6}}) }, func() *gltf.Document {
+			// Values near 0 keep differences smaller than DefaultTolerance in float32.
+			return newDocument([][3]float32{{5e-7, 2, 3}, {4, 5, 6}})
+		}, nil},
+		{"property", func() *gltf.Document { return newDocument(positions) }, func() *gltf.Document {
+			doc := newDocument(positions)
+			doc.Nodes[0].Name = "b"
+			doc.Nodes[0].Translation = [3]float64{1, 0, 0}
+			return doc
+		}, []diff.Change{
+			{Kind: diff.Modified, Path: "/nodes/0/name", From: "a", To: "b"},
+			{Kind: diff.Added, Path: "/nodes/0/translation", To: []any{1.0, 0.0, 0.0}},
+		}},
+		{"objects", func() *gltf.Document {
+			doc := newDocument(positions)
+			doc.Nodes = append(doc.Nodes, &gltf.Node{Name: "c"})
+			return doc
+		}, func() *gltf.Document {
+			doc := newDocument(positions)
+			doc.Meshes = []*gltf.Mesh{{Name: "m"}}
+			return doc
+		}, []diff.Change{
+			{Kind: diff.Added, Path: "/meshes", To: []any{map[string]any{"name": "m", "primitives": nil}}},
+			{Kind: diff.Removed, Path: "/nodes/1", From: map[string]any{"name": "c"}},
+		}},
+		{"data", func() *gltf.Document { return newDocument(positions) }, func() *gltf.Document {
+			return newDocument([][3]float32{{1, 2, 3}, {4, 5, 7}})
+		}, []diff.Change{
+			{Kind: diff.Modified, Path: "/accessors/0/data", From: positions, To: [][3]float32{{1, 2, 3}, {4, 5, 7}}},
+			{Kind: diff.Modified, Path: "/accessors/0/max/2", From: 6.0, To: 7.0},
+		}},
+		{"layout", func() *gltf.Document { return newDocument(positions) }, func() *gltf.Document {
+			doc := gltf.NewDocument()
+			modeler.WriteIndices(doc, []uint16{0, 1})
+			doc.Accessors = nil
+			modeler.WritePosition(doc, positions)
+			doc.Nodes = []*gltf.Node{{Name: "a"}}
+			return doc
+		}, []diff.Change{
+			{Kind: diff.Modified, Path: "/accessors/0/bufferView", From: 0.0, To: 1.0},
+			{Kind: diff.Modified, Path: "/bufferViews/0/byteLength", From: 24.0, To: 4.0},
+			{Kind: diff.Modified, Path: "/bufferViews/0/target", From: 34962.0, To: 34963.0},
+			{Kind: diff.Added, Path: "/bufferViews/1", To: map[string]any{"buffer": 0.0, "byteLength": 24.0, "byteOffset": 4.0, "target": 34962.0}},
+			{Kind: diff.Modified, Path: "/buffers/0/byteLength", From: 24.0, To: 28.0},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diff.Diff(tt.a(), tt.b())
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Error(t *testing.T) {
+	a := newDocument([][3]float32{{1, 2, 3}})
+	b := newDocument([][3]float32{{1, 2, 3}})
+	b.Buffers[0].Data = nil
+	if _, err := diff.Diff(a, b); err == nil || !strings.HasPrefix(err.Error(), "diff: ") {
+		t.Errorf("Diff() error = %v, want a diff error", err)
+	}
+}
+
+func TestDiffer_Diff(t *testing.T) {
+	a := newDocument([][3]float32{{1, 2, 3}})
+	b := newDocument([][3]float32{{1, 2, 3.1}})
+	d := diff.Differ{Tolerance: 0.2}
+	got, err := d.Diff(a, b)
+	if err != nil {
+		t.Fatalf("Differ.Diff() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Differ.Diff() = %v, want no changes", got)
+	}
+}
@@ -0,0 +1,326 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// EmbedPolicy defines how the Encoder stores buffers and images.
+type EmbedPolicy uint8
+
+const (
+	// EmbedDefault embeds the buffers without URI as data URIs,
+	// except the first one when encoding as GLB, which is stored in the binary chunk.
+	// Buffers with a relative URI are written to Fsys and images are kept as they are.
+	EmbedDefault EmbedPolicy = iota
+	// EmbedAll embeds all the buffers and images as base64 data URIs
+	// and encodes the document as a single self-contained glTF file, ignoring AsBinary.
+	EmbedAll
+	// EmbedNone writes all the buffers and images to Fsys as external files,
+	// generating a name for those without a relative URI.
+	// Images stored in buffer views are written to files too, their buffer views are kept,
+	// and relative image files are copied from Source.
+	EmbedNone
+	// EmbedPacked merges all the buffers and images into a single buffer
+	// and encodes the document as GLB, storing it in the binary chunk, ignoring AsBinary.
+	// The merged buffer keeps the name, extras and extensions of the first buffer.
+	EmbedPacked
+)
+
+// embedFile is an external file to be written by the Encoder.
+type embedFile struct {
+	name string
+	data []byte
+}
+
+// applyEmbedPolicy returns a copy of doc whose buffers and images follow the policy,
+// and the image files that have to be written.
+// Images stored in buffer views are not modified by EmbedAll.
+// doc is not modified.
+func (e *Encoder) applyEmbedPolicy(doc *Document) (*Document, []embedFile, error) {
+	if e.Embed == EmbedDefault {
+		return doc, nil, nil
+	}
+	tmp := *doc
+	tmp.Buffers = make([]*Buffer, len(doc.Buffers))
+	for i, b := range doc.Buffers {
+		b := *b
+		tmp.Buffers[i] = &b
+	}
+	tmp.Images = make([]*Image, len(doc.Images))
+	for i, im := range doc.Images {
+		im := *im
+		tmp.Images[i] = &im
+	}
+	switch e.Embed {
+	case EmbedAll:
+		return &tmp, nil, e.embedAll(&tmp)
+	case EmbedNone:
+		files, err := e.embedNone(&tmp)
+		return &tmp, files, err
+	case EmbedPacked:
+		return &tmp, nil, e.embedPacked(&tmp)
+	}
+	return nil, nil, fmt.Errorf("gltf: invalid embed policy %d", e.Embed)
+}
+
+func (e *Encoder) embedAll(doc *Document) error {
+	for i, b := range doc.Buffers {
+		if b.IsEmbeddedResource() {
+			continue
+		}
+		data, err := e.bufferData(b)
+		if err != nil {
+			return fmt.Errorf("gltf: cannot embed buffer %d: %w", i, err)
+		}
+		b.Data, b.Source = data, nil
+		b.EmbeddedResource()
+	}
+	for i, im := range doc.Images {
+		if im.BufferView != nil || strings.HasPrefix(im.URI, "data:") || isAbsoluteURI(im.URI) {
+			continue
+		}
+		data, err := e.imageData(im)
+		if err != nil {
+			return fmt.Errorf("gltf: cannot embed image %d: %w", i, err)
+		}
+		im.URI = "data:" + imageMimeType(im, data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+	}
+	return nil
+}
+
+func (e *Encoder) embedNone(doc *Document) ([]embedFile, error) {
+	used := make(map[string]bool)
+	for _, b := range doc.Buffers {
+		used[b.URI] = true
+	}
+	for _, im := range doc.Images {
+		used[im.URI] = true
+	}
+	for i, b := range doc.Buffers {
+		if b.URI != "" && !b.IsEmbeddedResource() {
+			continue
+		}
+		if b.Source == nil {
+			data, err := e.bufferData(b)
+			if err != nil {
+				return nil, fmt.Errorf("gltf: cannot externalize buffer %d: %w", i, err)
+			}
+			b.Data = data
+		}
+		b.URI = uniqueName(used, "buffer"+strconv.Itoa(i), ".bin")
+	}
+	var files []embedFile
+	for i, im := range doc.Images {
+		var (
+			data []byte
+			err  error
+		)
+		switch {
+		case im.BufferView != nil:
+			data, err = e.bufferViewData(doc, *im.BufferView)
+		case isAbsoluteURI(im.URI) && !strings.HasPrefix(im.URI, "data:"):
+			continue
+		default:
+			data, err = e.imageData(im)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gltf: cannot externalize image %d: %w", i, err)
+		}
+		if im.BufferView == nil && !strings.HasPrefix(im.URI, "data:") {
+			// Relative image files are copied next to the encoded document.
+			name := cleanURI(im.URI)
+			if !fs.ValidPath(name) {
+				return nil, fmt.Errorf("gltf: cannot externalize image %d: invalid path %q", i, im.URI)
+			}
+			files = append(files, embedFile{name: name, data: data})
+			continue
+		}
+		mimeType := imageMimeType(im, data)
+		im.URI = uniqueName(used, "image"+strconv.Itoa(i), imageExtension(mimeType))
+		im.MimeType = mimeType
+		im.BufferView = nil
+		files = append(files, embedFile{name: im.URI, data: data})
+	}
+	return files, nil
+}
+
+// bufferViewData returns the content of the buffer view with the given index.
+func (e *Encoder) bufferViewData(doc *Document, index int) ([]byte, error) {
+	if index < 0 || index >= len(doc.BufferViews) {
+		return nil, errors.New("buffer view index overflows")
+	}
+	bv := doc.BufferViews[index]
+	if bv.Buffer < 0 || bv.Buffer >= len(doc.Buffers) {
+		return nil, errors.New("buffer index overflows")
+	}
+	b := doc.Buffers[bv.Buffer]
+	if ra, ok := b.Source.(io.ReaderAt); ok {
+		data := make([]byte, bv.ByteLength)
+		_, err := ra.ReadAt(data, int64(bv.ByteOffset))
+		return data, err
+	}
+	if b.Source != nil {
+		return nil, errors.New("buffer source is not an io.ReaderAt")
+	}
+	data, err := e.bufferData(b)
+	if err != nil {
+		return nil, err
+	}
+	if bv.ByteOffset < 0 || bv.ByteLength < 0 || bv.ByteOffset+bv.ByteLength > len(data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data[bv.ByteOffset : bv.ByteOffset+bv.ByteLength], nil
+}
+
+func (e *Encoder) embedPacked(doc *Document) error {
+	packed := new(Buffer)
+	if len(doc.Buffers) > 0 {
+		// Keep the metadata of the first buffer, which usually is the only one.
+		first := doc.Buffers[0]
+		packed.Name, packed.Extras, packed.Extensions = first.Name, first.Extras, first.Extensions
+	}
+	offsets := make([]int, len(doc.Buffers))
+	for i, b := range doc.Buffers {
+		data, err := e.bufferData(b)
+		if err != nil {
+			return fmt.Errorf("gltf: cannot pack buffer %d: %w", i, err)
+		}
+		offsets[i] = appendPacked(packed, data)
+	}
+	views := doc.BufferViews
+	doc.BufferViews = make([]*BufferView, len(views))
+	for i, bv := range views {
+		bv := *bv
+		if bv.Buffer >= 0 && bv.Buffer < len(offsets) {
+			bv.ByteOffset += offsets[bv.Buffer]
+		}
+		bv.Buffer = 0
+		doc.BufferViews[i] = &bv
+	}
+	for i, im := range doc.Images {
+		if im.BufferView != nil || (isAbsoluteURI(im.URI) && !strings.HasPrefix(im.URI, "data:")) {
+			continue
+		}
+		data, err := e.imageData(im)
+		if err != nil {
+			return fmt.Errorf("gltf: cannot pack image %d: %w", i, err)
+		}
+		doc.BufferViews = append(doc.BufferViews, &BufferView{
+			ByteOffset: appendPacked(packed, data),
+			ByteLength: len(data),
+		})
+		im.MimeType = imageMimeType(im, data)
+		im.BufferView = Index(len(doc.BufferViews) - 1)
+		im.URI = ""
+	}
+	doc.Buffers = nil
+	if packed.ByteLength > 0 {
+		doc.Buffers = []*Buffer{packed}
+	}
+	return nil
+}
+
+// appendPacked appends data to buffer aligned to 4 bytes
+// and returns the offset where it has been written.
+func appendPacked(buffer *Buffer, data []byte) int {
+	if padding := len(buffer.Data) % 4; padding != 0 {
+		buffer.Data = append(buffer.Data, make([]byte, 4-padding)...)
+	}
+	offset := len(buffer.Data)
+	buffer.Data = append(buffer.Data, data...)
+	buffer.ByteLength = len(buffer.Data)
+	return offset
+}
+
+// bufferData returns the content of b, reading it from its Source or data URI if necessary.
+func (e *Encoder) bufferData(b *Buffer) ([]byte, error) {
+	switch {
+	case b.Source != nil:
+		data, err := io.ReadAll(io.LimitReader(bufferSource(b), int64(b.ByteLength)))
+		if err == nil && len(data) < b.ByteLength {
+			err = errBufferSourceShort
+		}
+		return data, err
+	case len(b.Data) > 0:
+		return b.Data, nil
+	case b.IsEmbeddedResource():
+		return b.marshalData()
+	case b.ByteLength == 0:
+		return nil, nil
+	}
+	return nil, errors.New("buffer data not loaded")
+}
+
+// imageData returns the content of im, either embedded in its URI
+// or stored in a file in Source.
+func (e *Encoder) imageData(im *Image) ([]byte, error) {
+	if strings.HasPrefix(im.URI, "data:") {
+		_, data, err := parseDataURI(im.URI)
+		return data, err
+	}
+	if im.URI == "" {
+		return nil, errors.New("image without URI nor buffer view")
+	}
+	if e.Source == nil {
+		return nil, errors.New("encoder without Source")
+	}
+	return fs.ReadFile(e.Source, cleanURI(im.URI))
+}
+
+// parseDataURI returns the media type and the decoded content of a base64 data URI.
+func parseDataURI(uri string) (string, []byte, error) {
+	header, content, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	mediaType, ok1 := strings.CutSuffix(header, ";base64")
+	if !ok || !ok1 {
+		return "", nil, errors.New("gltf: Invalid base64 content")
+	}
+	data, err := base64.StdEncoding.DecodeString(content)
+	return mediaType, data, err
+}
+
+// imageMimeType returns the MIME type of an image,
+// detecting it from the content if it is not defined.
+func imageMimeType(im *Image, data []byte) string {
+	if im.MimeType != "" {
+		return im.MimeType
+	}
+	if strings.HasPrefix(im.URI, "data:") {
+		if mediaType, _, err := parseDataURI(im.URI); err == nil && mediaType != "" {
+			return mediaType
+		}
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return "image/jpeg"
+	}
+	return "application/octet-stream"
+}
+
+func imageExtension(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	}
+	return ".bin"
+}
+
+// uniqueName returns a name made of base and ext that is not in used, and adds it to used.
+func uniqueName(used map[string]bool, base, ext string) string {
+	name := base + ext
+	for i := 1; used[name]; i++ {
+		name = base + "_" + strconv.Itoa(i) + ext
+	}
+	used[name] = true
+	return name
+}
@@ -1,13 +1,16 @@
 package gltf
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"io"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // A CreateFS provides access to a hierarchical file system.
@@ -56,12 +59,28 @@ func save(doc *Document, name string, asBinary bool) error {
 // An Encoder writes a glTF to an output stream.
 //
 // Only buffers with relative URIs will be written to Fsys.
+//
+// Document.Chunks are only written when encoding as GLB.
+//
+// Embed defines how buffers and images are stored, see EmbedPolicy.
+// EmbedAll always encodes as glTF and EmbedPacked as GLB, regardless of AsBinary.
+// Images with relative URIs are read from Source when the policy needs their content,
+// which usually is the directory of the document being encoded.
+// The encoded document is never modified.
+//
+// If Canonical is true the JSON content is written in canonical form,
+// so identical documents always produce identical bytes:
+// object keys are sorted, numbers use the shortest representation that round-trips
+// and required arrays and objects are never written as null.
 type Encoder struct {
-	AsBinary bool
-	Fsys     CreateFS
-	w        io.Writer
-	indent   string
-	prefix   string
+	AsBinary  bool
+	Canonical bool
+	Embed     EmbedPolicy
+	Fsys      CreateFS
+	Source    fs.FS
+	w         io.Writer
+	indent    string
+	prefix    string
 }
 
 // NewEncoder returns a new encoder that writes to w as a normal glTF file.
@@ -81,6 +100,18 @@ func NewEncoderFS(w io.Writer, fsys CreateFS) *Encoder {
 	}
 }
 
+// asBinary reports whether the document is encoded as GLB,
+// which is fixed by the EmbedAll and EmbedPacked policies.
+func (e *Encoder) asBinary() bool {
+	switch e.Embed {
+	case EmbedAll:
+		return false
+	case EmbedPacked:
+		return true
+	}
+	return e.AsBinary
+}
+
 // SetJSONIndent sets json encoded data to have provided prefix and indent settings
 func (e *Encoder) SetJSONIndent(prefix string, indent string) {
 	e.prefix = prefix
@@ -89,11 +120,23 @@ func (e *Encoder) SetJSONIndent(prefix string, indent string) {
 
 // Encode writes the encoding of doc to the stream.
 func (e *Encoder) Encode(doc *Document) error {
-	var err error
+	return e.EncodeContext(context.Background(), doc)
+}
+
+// EncodeContext is like Encode but checks ctx before writing the document,
+// the binary chunk and each external buffer, and while streaming buffers from their Source,
+// returning ctx.Err() once it is done.
+//
+// If Fsys implements CreateContextFS, ctx is passed to CreateContext.
+func (e *Encoder) EncodeContext(ctx context.Context, doc *Document) error {
+	doc, files, err := e.applyEmbedPolicy(doc)
+	if err != nil {
+		return err
+	}
 	var externalBufferIndex = 0
-	if e.AsBinary {
+	if e.asBinary() {
 		var hasBinChunk bool
-		hasBinChunk, err = e.encodeBinary(doc)
+		hasBinChunk, err = e.encodeBinary(ctx, doc)
 		if hasBinChunk {
 			externalBufferIndex = 1
 		}
@@ -103,6 +146,9 @@ func (e *Encoder) Encode(doc *Document) error {
 		if err != nil {
 			return err
 		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		_, err = e.w.Write(jsonData)
 	}
 	if err != nil {
@@ -111,10 +157,16 @@ func (e *Encoder) Encode(doc *Document) error {
 
 	for i := externalBufferIndex; i < len(doc.Buffers); i++ {
 		buf := doc.Buffers[i]
-		if len(buf.Data) == 0 || buf.URI == "" || buf.IsEmbeddedResource() {
+		if (len(buf.Data) == 0 && buf.Source == nil) || buf.URI == "" || buf.IsEmbeddedResource() {
 			continue
 		}
-		if err = e.encodeBuffer(buf); err != nil {
+		if err = e.encodeBuffer(ctx, buf); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		if err = e.encodeFile(ctx, f); err != nil {
 			return err
 		}
 	}
@@ -122,33 +174,50 @@ func (e *Encoder) Encode(doc *Document) error {
 	return err
 }
 
-func (e *Encoder) encodeBuffer(buffer *Buffer) error {
+func (e *Encoder) encodeFile(ctx context.Context, f embedFile) error {
+	if e.Fsys == nil {
+		return nil
+	}
+	w, err := createContext(ctx, e.Fsys, f.name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(f.data)
+	if err1 := w.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (e *Encoder) encodeBuffer(ctx context.Context, buffer *Buffer) error {
 	if err := validateBufferURI(buffer.URI); err != nil {
 		return err
 	}
 	if e.Fsys == nil {
 		return nil
 	}
-	uri, ok := sanitizeURI(buffer.URI)
-	if !ok {
+	if isAbsoluteURI(buffer.URI) {
 		return nil
 	}
-	w, err := e.Fsys.Create(uri)
+	w, err := createContext(ctx, e.Fsys, cleanURI(buffer.URI))
 	if err != nil {
 		return err
 	}
-	_, err = w.Write(buffer.Data)
+	err = writeBufferData(ctx, w, buffer)
 	if err1 := w.Close(); err == nil {
 		err = err1
 	}
 	return err
 }
 
-func (e *Encoder) encodeBinary(doc *Document) (bool, error) {
+func (e *Encoder) encodeBinary(ctx context.Context, doc *Document) (bool, error) {
 	jsonText, err := e.marshalJSONDoc(doc)
 	if err != nil {
 		return false, err
 	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
 	jsonHeader := chunkHeader{
 		Length: uint32(((len(jsonText) + 3) / 4) * 4),
 		Type:   glbChunkJSON,
@@ -170,6 +239,12 @@ func (e *Encoder) encodeBinary(doc *Document) (bool, error) {
 		binPaddedLength = ((doc.Buffers[0].ByteLength + 3) / 4) * 4
 		header.Length += uint32(8 + binPaddedLength)
 	}
+	for _, c := range doc.Chunks {
+		if c.Type == glbChunkJSON || c.Type == glbChunkBIN {
+			return false, errors.New("gltf: Invalid GLB chunk type")
+		}
+		header.Length += uint32(8 + ((len(c.Data)+3)/4)*4)
+	}
 
 	err = binary.Write(e.w, binary.LittleEndian, &header)
 	if err != nil {
@@ -179,6 +254,9 @@ func (e *Encoder) encodeBinary(doc *Document) (bool, error) {
 	e.w.Write(headerPadding)
 
 	if hasBinChunk {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
 		binBuffer := doc.Buffers[0]
 		binPadding := make([]byte, binPaddedLength-binBuffer.ByteLength)
 		for i := range binPadding {
@@ -186,45 +264,146 @@ func (e *Encoder) encodeBinary(doc *Document) (bool, error) {
 		}
 		binHeader := chunkHeader{Length: uint32(binPaddedLength), Type: glbChunkBIN}
 		binary.Write(e.w, binary.LittleEndian, &binHeader)
-		e.w.Write(binBuffer.Data)
+		if err = writeBufferData(ctx, e.w, binBuffer); err != nil {
+			return hasBinChunk, err
+		}
 		_, err = e.w.Write(binPadding)
 	}
+	for _, c := range doc.Chunks {
+		if err != nil {
+			break
+		}
+		err = e.encodeChunk(c)
+	}
 
 	return hasBinChunk, err
 }
 
+func (e *Encoder) encodeChunk(c Chunk) error {
+	padding := make([]byte, ((len(c.Data)+3)/4)*4-len(c.Data))
+	header := chunkHeader{Length: uint32(len(c.Data) + len(padding)), Type: c.Type}
+	if err := binary.Write(e.w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(c.Data); err != nil {
+		return err
+	}
+	_, err := e.w.Write(padding)
+	return err
+}
+
+var errBufferSourceShort = errors.New("gltf: buffer source is shorter than buffer.byteLength")
+
+// writeBufferData writes the content of buffer to w,
+// streaming it from buffer.Source if defined until ctx is done.
+func writeBufferData(ctx context.Context, w io.Writer, buffer *Buffer) error {
+	if buffer.Source == nil {
+		_, err := w.Write(buffer.Data)
+		return err
+	}
+	_, err := io.CopyN(w, &contextReader{ctx: ctx, r: bufferSource(buffer)}, int64(buffer.ByteLength))
+	if err == io.EOF {
+		err = errBufferSourceShort
+	}
+	return err
+}
+
+// bufferSource returns a reader positioned at the beginning of buffer.Source.
+func bufferSource(buffer *Buffer) io.Reader {
+	if ra, ok := buffer.Source.(io.ReaderAt); ok {
+		return io.NewSectionReader(ra, 0, int64(buffer.ByteLength))
+	}
+	return buffer.Source
+}
+
+// isAbsoluteURI reports whether uri has a scheme.
+func isAbsoluteURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme != ""
+}
+
+// escapeURI percent-encodes uri if it is relative,
+// as the Decoder stores relative URIs decoded.
+func escapeURI(uri string) string {
+	if uri == "" || isAbsoluteURI(uri) {
+		return uri
+	}
+	return (&url.URL{Path: cleanURI(uri)}).String()
+}
+
+// cleanURI returns the file name referenced by the relative and decoded uri.
+func cleanURI(uri string) string {
+	uri = strings.ReplaceAll(uri, "\\", "/")
+	uri = strings.ReplaceAll(uri, "/./", "/")
+	return strings.TrimPrefix(uri, "./")
+}
+
 // MarshalJSON marshal the document with the correct default values.
 func (e *Encoder) marshalJSONDoc(doc *Document) ([]byte, error) {
 	type alias Document
 	tmp := &struct {
 		CustomBuffers []*Buffer `json:"buffers,omitempty"`
 		Buffers       []*Buffer `json:"-"`
+		CustomImages  []*Image  `json:"images,omitempty"`
+		Images        []*Image  `json:"-"`
 		*alias
 	}{
 		CustomBuffers: make([]*Buffer, len(doc.Buffers)),
+		CustomImages:  make([]*Image, len(doc.Images)),
 		alias:         (*alias)(doc),
 	}
+	// Relative URIs are kept decoded in memory.
+	for i, im := range doc.Images {
+		tmp.CustomImages[i] = im
+		if uri := escapeURI(im.URI); uri != im.URI && !im.IsEmbeddedResource() {
+			tmpIm := *im
+			tmpIm.URI = uri
+			tmp.CustomImages[i] = &tmpIm
+		}
+	}
 	// Embed buffers without URI.
 	for i, buf := range doc.Buffers {
-		if i == 0 && e.AsBinary && buf.URI == "" {
+		if i == 0 && e.asBinary() && buf.URI == "" {
 			// First buffer will be encoded in the binary chunk.
 			tmp.CustomBuffers[i] = buf
 			continue
 		}
-		if len(buf.Data) > 0 && buf.URI == "" && !buf.IsEmbeddedResource() {
+		if (len(buf.Data) > 0 || buf.Source != nil) && buf.URI == "" && !buf.IsEmbeddedResource() {
+			data := buf.Data
+			if buf.Source != nil {
+				var err error
+				data, err = io.ReadAll(io.LimitReader(bufferSource(buf), int64(buf.ByteLength)))
+				if err != nil {
+					return nil, err
+				}
+				if len(data) < buf.ByteLength {
+					return nil, errBufferSourceShort
+				}
+			}
 			tmpBuf := &Buffer{
 				Extensions: buf.Extensions,
 				Extras:     buf.Extras,
 				Name:       buf.Name,
 				ByteLength: buf.ByteLength,
-				Data:       buf.Data,
+				Data:       data,
 			}
 			tmpBuf.EmbeddedResource()
 			tmp.CustomBuffers[i] = tmpBuf
+		} else if uri := escapeURI(buf.URI); uri != buf.URI && !buf.IsEmbeddedResource() {
+			tmpBuf := *buf
+			tmpBuf.URI = uri
+			tmp.CustomBuffers[i] = &tmpBuf
 		} else {
 			tmp.CustomBuffers[i] = buf
 		}
 	}
+	if e.Canonical {
+		data, err := json.Marshal(tmp)
+		if err != nil {
+			return nil, err
+		}
+		return canonicalJSON(data, e.prefix, e.indent)
+	}
 	if len(e.prefix) > 0 || len(e.indent) > 0 {
 		return json.MarshalIndent(tmp, e.prefix, e.indent)
 	}
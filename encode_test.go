@@ -2,10 +2,12 @@ package gltf_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"reflect"
 	"strings"
 	"testing"
@@ -37,6 +39,16 @@ func (m mockChunkReadHandler) Create(uri string) (io.WriteCloser, error) {
 	return mockFile{&m.MapFS[uri].Data}, nil
 }
 
+type mockContextFS struct {
+	mockChunkReadHandler
+	ctx context.Context
+}
+
+func (m *mockContextFS) CreateContext(ctx context.Context, uri string) (io.WriteCloser, error) {
+	m.ctx = ctx
+	return m.Create(uri)
+}
+
 func saveMemory(doc *Document, asBinary bool) (*Decoder, error) {
 	buff := new(bytes.Buffer)
 	m := mockChunkReadHandler{fstest.MapFS{}}
@@ -147,6 +159,412 @@ func TestEncoder_Encode_Buffers_Without_URI(t *testing.T) {
 	}
 }
 
+func TestEncoder_Encode_Canonical(t *testing.T) {
+	doc1 := &Document{
+		Asset:      Asset{Version: "2.0"},
+		Extensions: Extensions{"b_ext": json.RawMessage(`{"z": 1.50, "a": [1e2, -0.0, 1234567890123456789]}`), "a_ext": map[string]any{"y": 2, "x": 1}},
+		Extras:     map[string]any{"b": 1.0, "a": "text"},
+		Meshes:     []*Mesh{{Primitives: []*Primitive{{Targets: []PrimitiveAttributes{nil}}}}},
+		Nodes:      []*Node{{Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale}},
+		Skins:      []*Skin{{}},
+		Animations: []*Animation{{}},
+	}
+	doc2 := &Document{
+		Extensions: Extensions{"a_ext": json.RawMessage(`{"x":1,"y":2.0}`), "b_ext": json.RawMessage(`{"a":[100,0,1234567890123456789],"z":1.5}`)},
+		Extras:     map[string]any{"a": "text", "b": 1},
+		Meshes:     []*Mesh{{Primitives: []*Primitive{{Attributes: PrimitiveAttributes{}, Targets: []PrimitiveAttributes{{}}}}}},
+		Nodes:      []*Node{{}},
+		Skins:      []*Skin{{Joints: []int{}}},
+		Animations: []*Animation{{Channels: []*AnimationChannel{}, Samplers: []*AnimationSampler{}}},
+	}
+	want := `{"animations":[{"channels":[],"samplers":[]}],"asset":{"version":"2.0"},` +
+		`"extensions":{"a_ext":{"x":1,"y":2},"b_ext":{"a":[100,0,1234567890123456789],"z":1.5}},` +
+		`"extras":{"a":"text","b":1},"meshes":[{"primitives":[{"attributes":{},"targets":[{}]}]}],` +
+		`"nodes":[{}],"skins":[{"joints":[]}]}`
+	for i, doc := range []*Document{doc1, doc2} {
+		buf := new(bytes.Buffer)
+		e := NewEncoder(buf)
+		e.AsBinary = false
+		e.Canonical = true
+		if err := e.Encode(doc); err != nil {
+			t.Fatalf("Encoder.Encode() error = %v", err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("Encoder.Encode() doc%d = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestEncoder_EncodeContext(t *testing.T) {
+	doc := &Document{Buffers: []*Buffer{{ByteLength: 1, Data: []byte{1}}, {ByteLength: 1, URI: "a.bin", Data: []byte{2}}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, asBinary := range []bool{true, false} {
+		e := NewEncoder(new(bytes.Buffer))
+		e.AsBinary = asBinary
+		if err := e.EncodeContext(ctx, doc); err != context.Canceled {
+			t.Errorf("Encoder.EncodeContext() asBinary=%v error = %v, want %v", asBinary, err, context.Canceled)
+		}
+	}
+
+	type key struct{}
+	ctx = context.WithValue(context.Background(), key{}, 1)
+	m := &mockContextFS{mockChunkReadHandler: mockChunkReadHandler{fstest.MapFS{}}}
+	if err := NewEncoderFS(new(bytes.Buffer), m).EncodeContext(ctx, doc); err != nil {
+		t.Fatalf("Encoder.EncodeContext() error = %v", err)
+	}
+	if m.ctx != ctx {
+		t.Error("Encoder.EncodeContext() did not pass the context to CreateContext")
+	}
+	if got := m.MapFS["a.bin"].Data; !bytes.Equal(got, []byte{2}) {
+		t.Errorf("Encoder.EncodeContext() a.bin = %v, want [2]", got)
+	}
+}
+
+// cancelReader reads one byte at a time and calls cancel after the first one.
+type cancelReader struct {
+	r      io.Reader
+	cancel context.CancelFunc
+}
+
+func (c *cancelReader) Read(p []byte) (int, error) {
+	defer c.cancel()
+	return c.r.Read(p[:1])
+}
+
+func TestEncoder_EncodeContext_BufferSource(t *testing.T) {
+	for _, uri := range []string{"", "a.bin"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		src := &cancelReader{r: io.MultiReader(bytes.NewReader([]byte{1, 2, 3, 4})), cancel: cancel}
+		doc := &Document{Buffers: []*Buffer{{ByteLength: 4, URI: uri, Source: src}}}
+		e := NewEncoderFS(new(bytes.Buffer), mockChunkReadHandler{fstest.MapFS{}})
+		if err := e.EncodeContext(ctx, doc); err != context.Canceled {
+			t.Errorf("Encoder.EncodeContext() uri=%q error = %v, want %v", uri, err, context.Canceled)
+		}
+	}
+}
+
+func TestEncoder_Encode_BufferSource(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5}
+	tests := []struct {
+		name     string
+		asBinary bool
+		uri      string
+		source   func() io.Reader
+		wantErr  bool
+	}{
+		{"binChunk", true, "", func() io.Reader { return bytes.NewReader(data) }, false},
+		{"binChunkReader", true, "", func() io.Reader { return io.MultiReader(bytes.NewReader(data)) }, false},
+		{"external", false, "a.bin", func() io.Reader { return bytes.NewReader(data) }, false},
+		{"externalReader", true, "a.bin", func() io.Reader { return io.MultiReader(bytes.NewReader(data)) }, false},
+		{"embedded", false, "", func() io.Reader { return io.MultiReader(bytes.NewReader(data)) }, false},
+		{"short", true, "", func() io.Reader { return io.MultiReader(bytes.NewReader(data[:2])) }, true},
+		{"shortEmbedded", false, "", func() io.Reader { return bytes.NewReader(data[:2]) }, true},
+		{"shortExternal", false, "a.bin", func() io.Reader { return bytes.NewReader(data[:2]) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Buffers: []*Buffer{{ByteLength: len(data), URI: tt.uri, Source: tt.source()}}}
+			buf := new(bytes.Buffer)
+			m := mockChunkReadHandler{fstest.MapFS{}}
+			e := NewEncoderFS(buf, m)
+			e.AsBinary = tt.asBinary
+			err := e.Encode(doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Encoder.Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := new(Document)
+			if err := NewDecoderFS(buf, m).Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if !bytes.Equal(got.Buffers[0].Data, data) {
+				t.Errorf("Encoder.Encode() buffer = %v, want %v", got.Buffers[0].Data, data)
+			}
+		})
+	}
+}
+
+func TestEncoder_Encode_BufferSourceReaderAt(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5}
+	doc := &Document{Buffers: []*Buffer{{ByteLength: len(data), Source: bytes.NewReader(data)}}}
+	var first []byte
+	for i := 0; i < 2; i++ {
+		buf := new(bytes.Buffer)
+		if err := NewEncoder(buf).Encode(doc); err != nil {
+			t.Fatalf("Encoder.Encode() error = %v", err)
+		}
+		if i == 0 {
+			first = buf.Bytes()
+		} else if !bytes.Equal(buf.Bytes(), first) {
+			t.Errorf("Encoder.Encode() = %v, want %v", buf.Bytes(), first)
+		}
+	}
+}
+
+func TestEncoder_Encode_Chunks(t *testing.T) {
+	chunks := []Chunk{{Type: 0x41544144, Data: []byte("meta")}, {Type: 0x42, Data: []byte{1, 2, 3}}}
+	tests := []struct {
+		name    string
+		buffers []*Buffer
+	}{
+		{"withBin", []*Buffer{{ByteLength: 3, Data: []byte{1, 2, 3}}}},
+		{"withoutBin", []*Buffer{{ByteLength: 3, URI: "a.bin", Data: []byte{1, 2, 3}}}},
+		{"noBuffers", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Buffers: tt.buffers, Chunks: chunks}
+			d, err := saveMemory(doc, true)
+			if err != nil {
+				t.Fatalf("Encoder.Encode() error = %v", err)
+			}
+			got := new(Document)
+			if err := d.Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			want := []Chunk{chunks[0], {Type: 0x42, Data: []byte{1, 2, 3, 0}}}
+			if diff := deep.Equal(got.Chunks, want); diff != nil {
+				t.Errorf("Encoder.Encode() chunks = %v", diff)
+			}
+			if len(tt.buffers) > 0 && !bytes.Equal(got.Buffers[0].Data, tt.buffers[0].Data) {
+				t.Errorf("Encoder.Encode() buffer = %v, want %v", got.Buffers[0].Data, tt.buffers[0].Data)
+			}
+		})
+	}
+
+	for _, typ := range []uint32{0x4e4f534a, 0x004e4942} {
+		doc := &Document{Chunks: []Chunk{{Type: typ}}}
+		if err := NewEncoder(new(bytes.Buffer)).Encode(doc); err == nil {
+			t.Errorf("Encoder.Encode() chunk type %x expected error", typ)
+		}
+	}
+}
+
+func TestEncoder_Encode_URI(t *testing.T) {
+	doc := &Document{
+		Buffers: []*Buffer{
+			{ByteLength: 1, URI: "Box With Spaces.bin", Data: []byte{1}},
+			{ByteLength: 1, URI: "100%.bin", Data: []byte{2}},
+		},
+		Images: []*Image{
+			{URI: "dir/a b#1.png"},
+			{URI: "https://example.com/a.png"},
+			{URI: "data:image/png;base64,AA=="},
+		},
+	}
+	buf := new(bytes.Buffer)
+	m := mockChunkReadHandler{fstest.MapFS{}}
+	e := NewEncoderFS(buf, m)
+	e.AsBinary = false
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	for _, want := range []string{`"Box%20With%20Spaces.bin"`, `"100%25.bin"`, `"dir/a%20b%231.png"`, `"https://example.com/a.png"`, `"data:image/png;base64,AA=="`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Encoder.Encode() = %s, want %s", buf.String(), want)
+		}
+	}
+	for name, want := range map[string][]byte{"Box With Spaces.bin": {1}, "100%.bin": {2}} {
+		if f, ok := m.MapFS[name]; !ok || !bytes.Equal(f.Data, want) {
+			t.Errorf("Encoder.Encode() file %s not created", name)
+		}
+	}
+	got := new(Document)
+	if err := NewDecoderFS(buf, m).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	for i, b := range got.Buffers {
+		if b.URI != doc.Buffers[i].URI {
+			t.Errorf("Decoder.Decode() buffer URI = %s, want %s", b.URI, doc.Buffers[i].URI)
+		}
+	}
+	for i, im := range got.Images {
+		if im.URI != doc.Images[i].URI {
+			t.Errorf("Decoder.Decode() image URI = %s, want %s", im.URI, doc.Images[i].URI)
+		}
+	}
+	if doc.Buffers[0].URI != "Box With Spaces.bin" || doc.Images[0].URI != "dir/a b#1.png" {
+		t.Error("Encoder.Encode() modified the document")
+	}
+}
+
+func TestEncoder_Encode_Embed(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n1234")
+	newDoc := func() *Document {
+		return &Document{
+			Buffers: []*Buffer{
+				{ByteLength: 3, Data: []byte{1, 2, 3}, Name: "main", Extras: map[string]any{"a": 1.0}},
+				{ByteLength: 2, URI: "b.bin", Data: []byte{4, 5}},
+			},
+			BufferViews: []*BufferView{
+				{Buffer: 0, ByteLength: 3},
+				{Buffer: 1, ByteLength: 2},
+			},
+			Images: []*Image{
+				{URI: "a.png"},
+				{URI: "data:image/png;base64,iVBORw0KGgoxMjM0"},
+				{URI: "https://example.com/a.png"},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		embed      EmbedPolicy
+		asBinary   bool
+		wantBinary bool
+		wantFiles  []string
+		check      func(t *testing.T, doc *Document)
+	}{
+		{"all", EmbedAll, true, false, nil, func(t *testing.T, doc *Document) {
+			for i, b := range doc.Buffers {
+				if !b.IsEmbeddedResource() {
+					t.Errorf("buffer %d URI = %s, want embedded", i, b.URI)
+				}
+			}
+			if !strings.HasPrefix(doc.Images[0].URI, "data:image/png;base64,") || doc.Images[2].URI != "https://example.com/a.png" {
+				t.Errorf("image URIs = %s, %s", doc.Images[0].URI, doc.Images[2].URI)
+			}
+		}},
+		{"none", EmbedNone, false, false, []string{"a.png", "b.bin", "buffer0.bin", "image1.png"}, func(t *testing.T, doc *Document) {
+			if doc.Buffers[0].URI != "buffer0.bin" || doc.Buffers[1].URI != "b.bin" {
+				t.Errorf("buffer URIs = %s, %s", doc.Buffers[0].URI, doc.Buffers[1].URI)
+			}
+			if doc.Images[1].URI != "image1.png" || doc.Images[1].MimeType != "image/png" {
+				t.Errorf("image 1 = %s, %s", doc.Images[1].URI, doc.Images[1].MimeType)
+			}
+		}},
+		{"packed", EmbedPacked, false, true, nil, func(t *testing.T, doc *Document) {
+			if len(doc.Buffers) != 1 || doc.Buffers[0].URI != "" {
+				t.Fatalf("buffers = %v, want a single GLB buffer", doc.Buffers)
+			}
+			if doc.Buffers[0].Name != "main" || !reflect.DeepEqual(doc.Buffers[0].Extras, map[string]any{"a": 1.0}) {
+				t.Errorf("buffer metadata = %s, %v", doc.Buffers[0].Name, doc.Buffers[0].Extras)
+			}
+			want := []*BufferView{
+				{Buffer: 0, ByteLength: 3},
+				{Buffer: 0, ByteOffset: 4, ByteLength: 2},
+				{Buffer: 0, ByteOffset: 8, ByteLength: len(png)},
+				{Buffer: 0, ByteOffset: 20, ByteLength: len(png)},
+			}
+			if diff := deep.Equal(doc.BufferViews, want); diff != nil {
+				t.Errorf("buffer views = %v", diff)
+			}
+			for i, im := range doc.Images[:2] {
+				if im.URI != "" || im.BufferView == nil || *im.BufferView != 2+i || im.MimeType != "image/png" {
+					t.Errorf("image %d = %+v", i, im)
+				}
+			}
+			if doc.Images[2].URI != "https://example.com/a.png" {
+				t.Errorf("image 2 URI = %s", doc.Images[2].URI)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := new(MemFS)
+			w, _ := src.Create("a.png")
+			w.Write(png)
+			w.Close()
+			fsys := new(MemFS)
+			doc := newDoc()
+			buf := new(bytes.Buffer)
+			e := NewEncoderFS(buf, fsys)
+			e.Source = src
+			e.AsBinary = tt.asBinary
+			e.Embed = tt.embed
+			if err := e.Encode(doc); err != nil {
+				t.Fatalf("Encoder.Encode() error = %v", err)
+			}
+			if diff := deep.Equal(doc, newDoc()); diff != nil {
+				t.Errorf("Encoder.Encode() modified the document: %v", diff)
+			}
+			entries, _ := fs.ReadDir(fsys, ".")
+			var files []string
+			for _, e := range entries {
+				files = append(files, e.Name())
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("Encoder.Encode() files = %v, want %v", files, tt.wantFiles)
+			}
+			if isBinary := bytes.HasPrefix(buf.Bytes(), []byte("glTF")); isBinary != tt.wantBinary {
+				t.Errorf("Encoder.Encode() binary = %v, want %v", isBinary, tt.wantBinary)
+			}
+			got := new(Document)
+			if err := NewDecoderFS(buf, fsys).Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			tt.check(t, got)
+			var data []byte
+			for _, b := range got.Buffers {
+				data = append(data, b.Data...)
+			}
+			for _, want := range [][]byte{{1, 2, 3}, {4, 5}} {
+				if !bytes.Contains(data, want) {
+					t.Errorf("Decoder.Decode() buffers = %v, want %v", data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoder_Encode_EmbedNone(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n1234")
+	doc := &Document{
+		Buffers:     []*Buffer{{ByteLength: 12, Data: png}},
+		BufferViews: []*BufferView{{Buffer: 0, ByteLength: 12}},
+		Images: []*Image{
+			{BufferView: Index(0), MimeType: "image/png"},
+			{URI: "textures/a.png"},
+		},
+	}
+	src := new(MemFS)
+	w, _ := src.Create("textures/a.png")
+	w.Write(png)
+	w.Close()
+	fsys := new(MemFS)
+	buf := new(bytes.Buffer)
+	e := NewEncoderFS(buf, fsys)
+	e.Source = src
+	e.AsBinary = false
+	e.Embed = EmbedNone
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	for _, name := range []string{"buffer0.bin", "image0.png", "textures/a.png"} {
+		if data, err := fs.ReadFile(fsys, name); err != nil || (name != "buffer0.bin" && !bytes.Equal(data, png)) {
+			t.Errorf("Encoder.Encode() file %s = %v, %v", name, data, err)
+		}
+	}
+	got := new(Document)
+	if err := NewDecoderFS(buf, fsys).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if got.Images[0].URI != "image0.png" || got.Images[0].BufferView != nil {
+		t.Errorf("Encoder.Encode() image 0 = %+v", got.Images[0])
+	}
+
+	doc.Images = []*Image{{URI: "../a.png"}}
+	if err := e.Encode(doc); err == nil {
+		t.Error("Encoder.Encode() expected error with a non local image")
+	}
+}
+
+func TestEncoder_Encode_EmbedWithoutSource(t *testing.T) {
+	doc := &Document{Images: []*Image{{URI: "a.png"}}}
+	fsys := new(MemFS)
+	w, _ := fsys.Create("a.png")
+	w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	w.Close()
+	e := NewEncoderFS(new(bytes.Buffer), fsys)
+	e.Embed = EmbedAll
+	if err := e.Encode(doc); err == nil {
+		t.Error("Encoder.Encode() expected error reading images without Source")
+	}
+}
+
 func TestEncoder_Encode(t *testing.T) {
 	type args struct {
 		doc *Document
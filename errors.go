@@ -0,0 +1,328 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A DecodeError describes an error found while decoding a glTF or GLB document.
+// It can be retrieved from the errors returned by Decoder.Decode using errors.As.
+type DecodeError struct {
+	// Offset is the byte offset, counted from the beginning of the input,
+	// where the error was found. It is -1 if unknown.
+	Offset int64
+	// Line and Column are the 1-based position in the JSON content
+	// of the error or of the object that failed to decode.
+	// They are 0 if unknown.
+	Line, Column int
+	// Pointer is the JSON pointer (RFC 6901) of the object that failed to decode,
+	// such as "/buffers/1". It is empty if unknown, which only happens
+	// for syntax errors and for errors found while upgrading glTF 1.0 documents.
+	Pointer string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	var pos []string
+	if e.Pointer != "" {
+		pos = append(pos, "pointer "+strconv.Quote(e.Pointer))
+	}
+	if e.Line > 0 {
+		pos = append(pos, fmt.Sprintf("line %d, column %d", e.Line, e.Column))
+	}
+	if e.Offset >= 0 {
+		pos = append(pos, fmt.Sprintf("offset %d", e.Offset))
+	}
+	if len(pos) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (%s)", e.Err, strings.Join(pos, ", "))
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// jsonContent locates errors in the JSON content of the document being decoded.
+type jsonContent struct {
+	start      int64 // Offset of the JSON content in the input.
+	lines      lineReader
+	data       []byte // JSON-encoded document.
+	dataOffset int64  // Offset of data in the JSON content.
+}
+
+// errorAt returns a DecodeError located at offset, relative to the JSON content.
+func (c *jsonContent) errorAt(err error, offset int64) *DecodeError {
+	line, col := c.lines.position(offset)
+	return &DecodeError{Offset: c.start + offset, Line: line, Column: col, Err: err}
+}
+
+// jsonError returns a DecodeError located where the encoding/json err happened.
+// v is the value the content failed to decode into, or nil if unknown,
+// used to locate the errors that encoding/json does not report the position of.
+func (c *jsonContent) jsonError(err error, v any) *DecodeError {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// The offset points after the invalid character.
+		offset := syntaxErr.Offset
+		if offset > 0 {
+			offset--
+		}
+		if c.data != nil {
+			offset += c.dataOffset
+		}
+		return c.errorAt(err, offset)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if c.data != nil && errors.As(err, &typeErr) {
+		if v, ok := locateTypeError(c.data, typeErr); ok {
+			derr := c.errorAt(err, c.dataOffset+v.start)
+			derr.Pointer = v.pointer
+			return derr
+		}
+	}
+	if c.data != nil && v != nil {
+		if pointer, ok := errorPointer(c.data, reflect.TypeOf(v)); ok && pointer != "" {
+			return c.errorAtPointer(err, pointer)
+		}
+	}
+	return &DecodeError{Offset: -1, Err: err}
+}
+
+// errorAtPointer returns a DecodeError located at the value referenced by pointer.
+func (c *jsonContent) errorAtPointer(err error, pointer string) *DecodeError {
+	derr := &DecodeError{Offset: -1, Pointer: pointer, Err: err}
+	if offset := jsonPointerOffset(c.data, pointer); offset >= 0 {
+		offset += c.dataOffset
+		derr.Offset = c.start + offset
+		derr.Line, derr.Column = c.lines.position(offset)
+	}
+	return derr
+}
+
+// lineReader records the offset of every new line read from r,
+// so offsets can be translated to line and column positions.
+type lineReader struct {
+	r     io.Reader
+	n     int64
+	lines []int64
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i := bytes.IndexByte(p[:n], '\n'); i >= 0; {
+		l.lines = append(l.lines, l.n+int64(i))
+		j := bytes.IndexByte(p[i+1:n], '\n')
+		if j < 0 {
+			break
+		}
+		i += j + 1
+	}
+	l.n += int64(n)
+	return n, err
+}
+
+// position returns the 1-based line and column of offset.
+func (l *lineReader) position(offset int64) (int, int) {
+	i := sort.Search(len(l.lines), func(i int) bool { return l.lines[i] >= offset })
+	var start int64
+	if i > 0 {
+		start = l.lines[i-1] + 1
+	}
+	return i + 1, int(offset-start) + 1
+}
+
+// escapePointer escapes a JSON pointer reference token.
+func escapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+// jsonValue is the location of a value in a JSON-encoded document.
+type jsonValue struct {
+	pointer    string
+	start, end int64
+}
+
+// kind returns the JSON kind of the value, as reported by json.UnmarshalTypeError.
+func (v jsonValue) kind(data []byte) string {
+	switch c := data[v.start]; c {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// jsonValues returns the location of all the values in data in depth-first order.
+func jsonValues(data []byte) []jsonValue {
+	var values []jsonValue
+	dec := json.NewDecoder(bytes.NewReader(data))
+	walkJSONValue(dec, data, "", &values)
+	return values
+}
+
+func walkJSONValue(dec *json.Decoder, data []byte, pointer string, values *[]jsonValue) bool {
+	offset := dec.InputOffset()
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	i := len(*values)
+	*values = append(*values, jsonValue{pointer: pointer, start: offset})
+	tok, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return false
+			}
+			if !walkJSONValue(dec, data, pointer+"/"+escapePointer(key.(string)), values) {
+				return false
+			}
+		}
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if !walkJSONValue(dec, data, pointer+"/"+strconv.Itoa(i), values) {
+				return false
+			}
+		}
+	}
+	if _, ok := tok.(json.Delim); ok {
+		if _, err = dec.Token(); err != nil {
+			return false
+		}
+	}
+	(*values)[i].end = dec.InputOffset()
+	return true
+}
+
+// locateTypeError returns the value that caused err.
+//
+// Errors returned by nested json.Unmarshaler implementations report
+// the field path and the offset relative to the value being unmarshaled,
+// so the value is searched by matching the field path, the kind and the relative offset.
+func locateTypeError(data []byte, err *json.UnmarshalTypeError) (jsonValue, bool) {
+	values := jsonValues(data)
+	starts := make(map[string]int64, len(values))
+	for _, v := range values {
+		starts[v.pointer] = v.start
+	}
+	var suffix string
+	if err.Field != "" {
+		suffix = "/" + strings.ReplaceAll(err.Field, ".", "/")
+	}
+	for _, v := range values {
+		if !strings.HasSuffix(v.pointer, suffix) || v.kind(data) != err.Value {
+			continue
+		}
+		base, ok := starts[strings.TrimSuffix(v.pointer, suffix)]
+		if !ok {
+			continue
+		}
+		if rel := err.Offset + base; v.start < rel && rel <= v.end {
+			return v, true
+		}
+	}
+	return jsonValue{}, false
+}
+
+// errorPointer returns the JSON pointer, relative to data, of the deepest value
+// that fails to decode into its Go type when decoded on its own,
+// or false if data decodes into t without errors.
+//
+// This locates the errors returned by json.Unmarshaler implementations,
+// which encoding/json reports without position.
+func errorPointer(data []byte, t reflect.Type) (string, bool) {
+	if json.Unmarshal(data, reflect.New(t).Interface()) == nil {
+		return "", false
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, v := range jsonValues(data) {
+		if v.pointer == "" || strings.Count(v.pointer, "/") != 1 {
+			// Only the direct children are checked, deeper values are checked recursively.
+			continue
+		}
+		var elem reflect.Type
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := jsonField(t, unescapePointer(v.pointer[1:]))
+			if !ok {
+				continue
+			}
+			elem = f.Type
+		case reflect.Slice, reflect.Array, reflect.Map:
+			elem = t.Elem()
+		default:
+			continue
+		}
+		if p, ok := errorPointer(data[v.start:v.end], elem); ok {
+			return v.pointer + p, true
+		}
+	}
+	return "", true
+}
+
+// jsonField returns the field of t that encoding/json decodes the name key into.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f, true
+		}
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &f
+		}
+	}
+	if folded != nil {
+		return *folded, true
+	}
+	return reflect.StructField{}, false
+}
+
+// unescapePointer unescapes a JSON pointer reference token.
+func unescapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~1", "/")
+	return strings.ReplaceAll(s, "~0", "~")
+}
+
+// jsonPointerOffset returns the offset of the value referenced by pointer, or -1 if not found.
+func jsonPointerOffset(data []byte, pointer string) int64 {
+	for _, v := range jsonValues(data) {
+		if v.pointer == pointer {
+			return v.start
+		}
+	}
+	return -1
+}
@@ -0,0 +1,171 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDecoder_Decode_DecodeError(t *testing.T) {
+	glb := func(content string, bin []byte) []byte {
+		var buf bytes.Buffer
+		header := glbHeader{
+			Magic:      glbHeaderMagic,
+			Version:    2,
+			Length:     uint32(20 + len(content) + len(bin)),
+			JSONHeader: chunkHeader{Length: uint32(len(content)), Type: glbChunkJSON},
+		}
+		binary.Write(&buf, binary.LittleEndian, &header)
+		buf.WriteString(content)
+		buf.Write(bin)
+		return buf.Bytes()
+	}
+	tests := []struct {
+		name string
+		d    *Decoder
+		want DecodeError
+	}{
+		{"syntax", NewDecoder(bytes.NewBufferString("{\n  \"asset\": {\n    \"version\": 2.0,\n  }\n}")),
+			DecodeError{Offset: 37, Line: 4, Column: 3}},
+		{"type", NewDecoder(bytes.NewBufferString("{\n\"buffers\": [\n{\"byteLength\": 1},\n{\"byteLength\": \"a\"}\n]\n}")),
+			DecodeError{Offset: 49, Line: 4, Column: 16, Pointer: "/buffers/1/byteLength"}},
+		{"typeArray", NewDecoder(bytes.NewBufferString(`{"nodes": [{"children": {}}]}`)),
+			DecodeError{Offset: 24, Line: 1, Column: 25, Pointer: "/nodes/0/children"}},
+		{"typeNested", NewDecoder(bytes.NewBufferString(`{"materials": [{"doubleSided": true}, {"doubleSided": "x"}]}`)),
+			DecodeError{Offset: 54, Line: 1, Column: 55, Pointer: "/materials/1/doubleSided"}},
+		{"buffer", NewDecoder(bytes.NewBufferString("{\n\"buffers\": [\n{\"byteLength\": 1, \"uri\": \"a.bin\"},\n{\"byteLength\": 0}\n]\n}")),
+			DecodeError{Offset: 50, Line: 4, Column: 1, Pointer: "/buffers/1"}},
+		{"externalBuffer", NewDecoderFS(bytes.NewBufferString(`{"buffers": [{"byteLength": 1, "uri": "a.bin"}]}`), fstest.MapFS{}),
+			DecodeError{Offset: 13, Line: 1, Column: 14, Pointer: "/buffers/0"}},
+		{"glbHeader", NewDecoder(bytes.NewBuffer([]byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00, 0x40, 0x0b, 0x00, 0x00, 0x5c, 0x06, 0x00, 0x00, 0x4a, 0x52, 0x4f, 0x4e})),
+			DecodeError{Offset: 12}},
+		{"glbJSON", NewDecoder(bytes.NewBuffer(glb(`{"buffers": 1}`, nil))),
+			DecodeError{Offset: 32, Line: 1, Column: 13, Pointer: "/buffers"}},
+		{"glbBin", NewDecoder(bytes.NewBuffer(glb(`{"buffers": [{"byteLength": 8}]}`, []byte{4, 0, 0, 0, 0x42, 0x49, 0x4e, 0x00, 1, 2, 3, 4}))),
+			DecodeError{Offset: 52, Line: 1, Column: 14, Pointer: "/buffers/0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.d.Decode(new(Document))
+			var derr *DecodeError
+			if !errors.As(err, &derr) {
+				t.Fatalf("Decoder.Decode() error = %v, want *DecodeError", err)
+			}
+			tt.want.Err = derr.Err
+			if *derr != tt.want {
+				t.Errorf("Decoder.Decode() error = %+v, want %+v", *derr, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeError_Unwrap(t *testing.T) {
+	err := NewDecoderFS(bytes.NewBufferString(`{"buffers": [{"byteLength": 1, "uri": "a.bin"}]}`), fstest.MapFS{}).Decode(new(Document))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Decoder.Decode() error = %v, want fs.ErrNotExist", err)
+	}
+	err = NewDecoder(bytes.NewBufferString("")).Decode(new(Document))
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Decoder.Decode() error = %v, want io.EOF", err)
+	}
+}
+
+func TestDecodeError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		e    *DecodeError
+		want string
+	}{
+		{"unknown", &DecodeError{Offset: -1, Err: errors.New("a")}, "a"},
+		{"offset", &DecodeError{Offset: 12, Err: errors.New("a")}, "a (offset 12)"},
+		{"all", &DecodeError{Offset: 12, Line: 2, Column: 3, Pointer: "/buffers/0", Err: errors.New("a")}, `a (pointer "/buffers/0", line 2, column 3, offset 12)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.Error(); got != tt.want {
+				t.Errorf("DecodeError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_jsonPointerOffset(t *testing.T) {
+	data := []byte(`{"a/b": [1, {"c~": true}], "d": null}`)
+	tests := []struct {
+		pointer string
+		want    int64
+	}{
+		{"", 0},
+		{"/a~1b", 8},
+		{"/a~1b/0", 9},
+		{"/a~1b/1/c~0", 19},
+		{"/d", 32},
+		{"/e", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			if got := jsonPointerOffset(data, tt.pointer); got != tt.want {
+				t.Errorf("jsonPointerOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type failingValue struct{}
+
+func (*failingValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "0" {
+		return errors.New("zero value")
+	}
+	return nil
+}
+
+type failingDoc struct {
+	Nodes []struct {
+		Name  string        `json:"name"`
+		Value *failingValue `json:"value"`
+	} `json:"nodes"`
+	Values map[string]failingValue
+}
+
+func Test_errorPointer(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   string
+		wantOk bool
+	}{
+		{"valid", `{"nodes": [{"value": 1}]}`, "", false},
+		{"slice", `{"nodes": [{"value": 1}, {"name": "a", "value": 0}]}`, "/nodes/1/value", true},
+		{"map", `{"values": {"a/b": 1, "c": 0}}`, "/values/c", true},
+		{"folded", `{"NODES": [{"VALUE": 0}]}`, "/NODES/0/VALUE", true},
+		{"escaped", `{"values": {"a/b": 0}}`, "/values/a~1b", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := errorPointer([]byte(tt.data), reflect.TypeOf(new(failingDoc)))
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("errorPointer() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestJSONContent_jsonError_Unmarshaler(t *testing.T) {
+	data := []byte(`{"nodes": [{"value": 1}, {"value": 0}]}`)
+	c := &jsonContent{data: data}
+	err := json.Unmarshal(data, new(failingDoc))
+	derr := c.jsonError(err, new(failingDoc))
+	if derr.Pointer != "/nodes/1/value" || derr.Offset != 35 || !errors.Is(derr, err) {
+		t.Errorf("jsonError() = %+v, want pointer /nodes/1/value at offset 35", derr)
+	}
+	if derr := c.jsonError(err, nil); derr.Pointer != "" || derr.Offset != -1 {
+		t.Errorf("jsonError() without target = %+v, want unknown location", derr)
+	}
+}
@@ -0,0 +1,14 @@
+// Package meshquantization defines the KHR_mesh_quantization extension.
+//
+// The extension has no JSON properties, it allows vertex attributes
+// and morph targets to be stored as 8 and 16 bit integers.
+// As documents using it can't be read by clients that don't support it,
+// it must be listed in both Document.ExtensionsUsed and Document.ExtensionsRequired.
+//
+// See the modeler package to read and write quantized attributes.
+package meshquantization
+
+const (
+	// ExtensionName defines the KHR_mesh_quantization unique key.
+	ExtensionName = "KHR_mesh_quantization"
+)
@@ -3,6 +3,7 @@ package gltf
 import (
 	"encoding/base64"
 	"errors"
+	"io"
 	"strings"
 	"sync"
 )
@@ -48,6 +49,16 @@ type Document struct {
 	Scenes             []*Scene      `json:"scenes,omitempty"`
 	Skins              []*Skin       `json:"skins,omitempty"`
 	Textures           []*Texture    `json:"textures,omitempty"`
+	// Chunks are the GLB chunks found after the JSON and BIN chunks,
+	// which are written back after them when encoding as GLB.
+	Chunks []Chunk `json:"-"`
+}
+
+// A Chunk is a GLB chunk whose type is neither JSON nor BIN,
+// such as application specific metadata.
+type Chunk struct {
+	Type uint32
+	Data []byte // Padded with zeros to a multiple of 4 bytes when encoding.
 }
 
 // NewDocument returns a new Document with sane defaults.
@@ -109,6 +120,11 @@ type SparseIndices struct {
 // A Buffer points to binary geometry, animation, or skins.
 // If Data length is 0 and the Buffer is an external resource the Data won't be flushed,
 // which can be useful when there is no need to load data in memory.
+//
+// If Source is not nil the Encoder streams ByteLength bytes from it instead of writing Data,
+// so big buffers don't have to be loaded in memory. If Source also implements io.ReaderAt
+// it is read from the beginning on every encoding, otherwise it can only be encoded once.
+// Sources of buffers that are embedded in the JSON content are read in memory.
 type Buffer struct {
 	Extensions Extensions `json:"extensions,omitempty"`
 	Extras     any        `json:"extras,omitempty"`
@@ -116,6 +132,7 @@ type Buffer struct {
 	URI        string     `json:"uri,omitempty"`
 	ByteLength int        `json:"byteLength"`
 	Data       []byte     `json:"-"`
+	Source     io.Reader  `json:"-"`
 }
 
 // IsEmbeddedResource returns true if the buffer points to an embedded resource.
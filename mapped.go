@@ -0,0 +1,63 @@
+package gltf
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// A MappedDocument is a Document decoded from a memory-mapped file.
+//
+// The Data of the buffer stored in the GLB binary chunk points directly into the mapping
+// instead of being copied, the rest of buffers are loaded in memory as usual.
+// The mapped Data is read-only, writing to it will crash the program,
+// and it must not be used after calling Close.
+// Truncating the file while it is mapped raises a SIGBUS
+// when accessing the Data past the new end of the file.
+// The remaining Document fields, including the Chunks data,
+// are not backed by the mapping and can be used after Close.
+type MappedDocument struct {
+	*Document
+	data  []byte
+	unmap func([]byte) error
+}
+
+// OpenMapped opens the glTF or GLB file specified by name memory-mapping its content
+// and returns the MappedDocument, which must be closed after use.
+//
+// Memory-mapping is only supported on Linux,
+// on other platforms the file is read in memory.
+func OpenMapped(name string) (*MappedDocument, error) {
+	data, unmap, err := mmapFile(name)
+	if err != nil {
+		return nil, err
+	}
+	m := &MappedDocument{Document: new(Document), data: data, unmap: unmap}
+	dec := newMappedDecoder(data, os.DirFS(filepath.Dir(name)))
+	if err := dec.Decode(m.Document); err != nil {
+		m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Close unmaps the file. It is safe to call Close multiple times.
+func (m *MappedDocument) Close() error {
+	data := m.data
+	m.data = nil
+	if data == nil || m.unmap == nil {
+		return nil
+	}
+	return m.unmap(data)
+}
+
+// newMappedDecoder returns a new decoder that reads from the memory-mapped data
+// without copying the GLB binary chunk.
+func newMappedDecoder(data []byte, fsys fs.FS) *Decoder {
+	mr := bytes.NewReader(data)
+	d := NewDecoderFS(mr, fsys)
+	d.mapped = data
+	d.mr = mr
+	return d
+}
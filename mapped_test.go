@@ -0,0 +1,116 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestOpenMapped(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb", false},
+		{"testdata/Cube/glTF/Cube.gltf", false},
+		{"testdata/Cube/glTF/Cube.bin", true},
+		{"testdata/none.glb", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenMapped(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenMapped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want, err := Open(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(got.Document, want); diff != nil {
+				t.Errorf("OpenMapped() = %v", diff)
+			}
+			if err := got.Close(); err != nil {
+				t.Errorf("MappedDocument.Close() error = %v", err)
+			}
+			if err := got.Close(); err != nil {
+				t.Errorf("MappedDocument.Close() second call error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_Mapped(t *testing.T) {
+	data := readFile("testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb")
+	doc := new(Document)
+	if err := newMappedDecoder(data, nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	bin := doc.Buffers[0].Data
+	if len(bin) == 0 || cap(bin) != len(bin) || !bytes.Contains(data, bin) {
+		t.Fatalf("Decoder.Decode() buffer is not a view of the input")
+	}
+	if &data[len(data)-len(bin)-padding(len(bin))] != &bin[0] {
+		t.Error("Decoder.Decode() buffer has been copied")
+	}
+
+	// Extension chunks are not views of the input.
+	glb := append(append([]byte(nil), data...), 4, 0, 0, 0, 'M', 'E', 'T', 'A', 'a', 'b', 'c', 'd')
+	binary.LittleEndian.PutUint32(glb[8:], uint32(len(glb)))
+	doc = new(Document)
+	if err := newMappedDecoder(glb, nil).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if len(doc.Chunks) != 1 || &doc.Chunks[0].Data[0] == &glb[len(glb)-4] {
+		t.Errorf("Decoder.Decode() chunks = %v, want a copy of the input", doc.Chunks)
+	}
+
+	// Truncated binary chunk.
+	if err := newMappedDecoder(data[:len(data)-len(bin)/2], nil).Decode(new(Document)); err == nil {
+		t.Error("Decoder.Decode() expected error")
+	}
+}
+
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestDecoder_Decode_MappedLarge(t *testing.T) {
+	var buf bytes.Buffer
+	doc := NewDocument()
+	doc.Buffers = []*Buffer{{ByteLength: 1 << 20, Data: make([]byte, 1<<20)}}
+	if err := NewEncoder(&buf).Encode(doc); err != nil {
+		t.Fatal(err)
+	}
+	glb := append(buf.Bytes(), 4, 0, 0, 0, 'M', 'E', 'T', 'A', 'a', 'b', 'c', 'd')
+	binary.LittleEndian.PutUint32(glb[8:], uint32(len(glb)))
+	d := newMappedDecoder(glb, nil)
+	cr := &countingReader{r: d.cr.r}
+	d.cr.r = cr
+	doc = new(Document)
+	if err := d.Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if cr.n >= 1<<20 {
+		t.Errorf("Decoder.Decode() read %d bytes through the reader, want the binary chunk to be skipped", cr.n)
+	}
+	if len(doc.Buffers[0].Data) != 1<<20 || len(doc.Chunks) != 1 || string(doc.Chunks[0].Data) != "abcd" {
+		t.Errorf("Decoder.Decode() = %d bytes, chunks %v", len(doc.Buffers[0].Data), doc.Chunks)
+	}
+}
@@ -0,0 +1,175 @@
+package gltf
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A MemFS is an in-memory file system that implements CreateFS,
+// so it can be used with both NewEncoderFS and NewDecoderFS
+// to encode and decode documents with external resources without touching the disk.
+//
+// The zero value is an empty file system ready to use.
+// A MemFS is safe for concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// Open implements fs.FS.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if data, ok := m.files[name]; ok {
+		return &memFile{info: memFileInfo{name: path.Base(name), size: len(data)}, r: bytes.NewReader(data)}, nil
+	}
+	// Directories are implicitly defined by the files they contain.
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	var entries []fs.DirEntry
+	dirs := make(map[string]bool)
+	for fname, data := range m.files {
+		rest, ok := strings.CutPrefix(fname, prefix)
+		if !ok {
+			continue
+		}
+		if dir, _, ok := strings.Cut(rest, "/"); ok {
+			if !dirs[dir] {
+				dirs[dir] = true
+				entries = append(entries, memFileInfo{name: dir, dir: true})
+			}
+		} else {
+			entries = append(entries, memFileInfo{name: rest, size: len(data)})
+		}
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return &memDir{info: memFileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(data), nil
+}
+
+// Create creates or truncates the named file.
+// The content is available to readers once the returned writer is closed.
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	m.store(name, nil)
+	return &memWriter{fs: m, name: name}, nil
+}
+
+func (m *MemFS) store(name string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(map[string][]byte)
+	}
+	m.files[name] = data
+}
+
+type memWriter struct {
+	fs   *MemFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *memWriter) Close() error {
+	w.fs.store(w.name, w.buf.Bytes())
+	return nil
+}
+
+// memFileInfo implements fs.FileInfo and fs.DirEntry.
+type memFileInfo struct {
+	name string
+	size int
+	dir  bool
+}
+
+func (fi memFileInfo) Name() string               { return fi.name }
+func (fi memFileInfo) Size() int64                { return int64(fi.size) }
+func (fi memFileInfo) ModTime() time.Time         { return time.Time{} }
+func (fi memFileInfo) IsDir() bool                { return fi.dir }
+func (fi memFileInfo) Sys() any                   { return nil }
+func (fi memFileInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi memFileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+func (fi memFileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+type memFile struct {
+	info memFileInfo
+	r    *bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Read(p []byte) (int, error) { return f.r.Read(p) }
+func (f *memFile) Close() error               { return nil }
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	return f.r.ReadAt(p, off)
+}
+
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.entries[d.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
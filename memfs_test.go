@@ -0,0 +1,55 @@
+package gltf
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-test/deep"
+)
+
+func TestMemFS(t *testing.T) {
+	var m MemFS
+	for name, data := range map[string]string{"a.bin": "abc", "dir/b.bin": "de", "dir/sub/c.png": "", "e.gltf": "{}"} {
+		w, err := m.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fstest.TestFS(&m, "a.bin", "dir/b.bin", "dir/sub/c.png", "e.gltf"); err != nil {
+		t.Error(err)
+	}
+	if _, err := m.Create("../a.bin"); err == nil {
+		t.Error("MemFS.Create() expected error")
+	}
+	if _, err := m.Open("none.bin"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("MemFS.Open() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestMemFS_RoundTrip(t *testing.T) {
+	doc, err := Open("testdata/Box With Spaces/glTF/Box With Spaces.gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(MemFS)
+	var buf bytes.Buffer
+	e := NewEncoderFS(&buf, m)
+	e.AsBinary = false
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	got := new(Document)
+	if err := NewDecoderFS(&buf, m).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if diff := deep.Equal(got, doc); diff != nil {
+		t.Errorf("Decoder.Decode() = %v", diff)
+	}
+}
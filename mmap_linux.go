@@ -0,0 +1,33 @@
+package gltf
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the named file in memory as read-only.
+func mmapFile(name string) ([]byte, func([]byte) error, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		// Empty files can't be mapped.
+		return []byte{}, nil, nil
+	}
+	if int64(int(size)) != size {
+		return nil, nil, errors.New("gltf: file too large to be memory-mapped")
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: name, Err: err}
+	}
+	return data, syscall.Munmap, nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package gltf
+
+import "os"
+
+// mmapFile reads the named file in memory,
+// as memory-mapping is not supported on this platform.
+func mmapFile(name string) ([]byte, func([]byte) error, error) {
+	data, err := os.ReadFile(name)
+	return data, nil, err
+}
@@ -0,0 +1,184 @@
+package modeler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/binary"
+)
+
+// An AnimationBuilder writes the keyframes of an animation into a document
+// and wires the resulting samplers and channels.
+//
+// Keyframe times are written as input accessors with min and max,
+// and identical times are shared by all the samplers of the builder.
+//
+// Values are laid out as defined by the glTF spec: one value per keyframe
+// for LINEAR and STEP interpolations and an in-tangent, a value and an out-tangent
+// per keyframe, in that order, for CUBICSPLINE.
+type AnimationBuilder struct {
+	doc       *gltf.Document
+	w         *Writer // nil writes into the last buffer of doc.
+	opts      []WriteOption
+	animation *gltf.Animation
+	inputs    map[string]int
+	index     *int // Index of the animation in doc once built.
+}
+
+// NewAnimationBuilder returns a builder that writes into doc an animation called name.
+// opts are applied to the input and output accessors.
+func NewAnimationBuilder(doc *gltf.Document, name string, opts ...WriteOption) *AnimationBuilder {
+	return &AnimationBuilder{
+		doc:       doc,
+		opts:      opts,
+		animation: &gltf.Animation{Name: name},
+		inputs:    make(map[string]int),
+	}
+}
+
+// NewAnimationBuilder returns a builder that writes into the buffer of w an animation called name.
+func (w *Writer) NewAnimationBuilder(name string, opts ...WriteOption) *AnimationBuilder {
+	b := NewAnimationBuilder(w.doc, name, opts...)
+	b.w = w
+	return b
+}
+
+// Translation animates the translation of node.
+func (b *AnimationBuilder) Translation(node int, interpolation gltf.Interpolation, times []float32, values [][3]float32) error {
+	return b.add(node, gltf.TRSTranslation, interpolation, times, values, len(values))
+}
+
+// Rotation animates the rotation of node, values are quaternions in (x, y, z, w) order.
+func (b *AnimationBuilder) Rotation(node int, interpolation gltf.Interpolation, times []float32, values [][4]float32) error {
+	return b.add(node, gltf.TRSRotation, interpolation, times, values, len(values))
+}
+
+// Scale animates the scale of node.
+func (b *AnimationBuilder) Scale(node int, interpolation gltf.Interpolation, times []float32, values [][3]float32) error {
+	return b.add(node, gltf.TRSScale, interpolation, times, values, len(values))
+}
+
+// Weights animates the morph target weights of node.
+// values contains one weight per morph target for each value of the layout.
+func (b *AnimationBuilder) Weights(node int, interpolation gltf.Interpolation, times []float32, values []float32) error {
+	targets := b.morphTargets(node)
+	if targets == 0 && len(times) > 0 {
+		// The morph targets of node are unknown, infer them from the keyframes.
+		targets = len(values) / keyframeValues(interpolation, len(times))
+	}
+	if targets == 0 || len(values)%targets != 0 {
+		return fmt.Errorf("gltf: invalid number of weights %d for %d morph targets", len(values), targets)
+	}
+	n := len(values) / targets
+	return b.add(node, gltf.TRSWeights, interpolation, times, values, n)
+}
+
+// keyframeValues returns the number of values required by n keyframes.
+func keyframeValues(interpolation gltf.Interpolation, n int) int {
+	if interpolation == gltf.InterpolationCubicSpline {
+		return 3 * n
+	}
+	return n
+}
+
+// morphTargets returns the number of morph targets of the mesh instantiated by node,
+// or 0 if unknown.
+func (b *AnimationBuilder) morphTargets(node int) int {
+	if node < 0 || node >= len(b.doc.Nodes) || b.doc.Nodes[node].Mesh == nil {
+		return 0
+	}
+	mesh := *b.doc.Nodes[node].Mesh
+	if mesh < 0 || mesh >= len(b.doc.Meshes) || len(b.doc.Meshes[mesh].Primitives) == 0 {
+		return 0
+	}
+	return len(b.doc.Meshes[mesh].Primitives[0].Targets)
+}
+
+// Build adds the animation to doc and returns its index.
+// It returns an error if no channel has been added.
+//
+// Calling Build again returns the same index without adding the animation twice,
+// channels added in between are already part of it.
+func (b *AnimationBuilder) Build() (int, error) {
+	if b.index != nil {
+		return *b.index, nil
+	}
+	if len(b.animation.Channels) == 0 {
+		return 0, errors.New("gltf: animation without channels")
+	}
+	b.doc.Animations = append(b.doc.Animations, b.animation)
+	b.index = gltf.Index(len(b.doc.Animations) - 1)
+	return *b.index, nil
+}
+
+// add writes a sampler with the input times and output values, which contains n elements,
+// and a channel that targets node and path with it.
+func (b *AnimationBuilder) add(node int, path gltf.TRSProperty, interpolation gltf.Interpolation, times []float32, values any, n int) error {
+	if node < 0 || node >= len(b.doc.Nodes) {
+		return fmt.Errorf("gltf: node index %d overflows", node)
+	}
+	if err := checkKeyframes(interpolation, times, n); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	b.animation.Samplers = append(b.animation.Samplers, &gltf.AnimationSampler{
+		Input:         b.writeInput(times),
+		Interpolation: interpolation,
+		Output:        b.writer().WriteAccessor(gltf.TargetNone, values, b.opts...),
+	})
+	b.animation.Channels = append(b.animation.Channels, &gltf.AnimationChannel{
+		Sampler: len(b.animation.Samplers) - 1,
+		Target:  gltf.AnimationChannelTarget{Node: gltf.Index(node), Path: path},
+	})
+	return nil
+}
+
+func checkKeyframes(interpolation gltf.Interpolation, times []float32, n int) error {
+	if len(times) == 0 {
+		return errors.New("gltf: animation without keyframes")
+	}
+	for i, t := range times {
+		if t < 0 || math.IsNaN(float64(t)) || (i > 0 && t <= times[i-1]) {
+			return errors.New("gltf: keyframe times must be positive and strictly increasing")
+		}
+	}
+	switch interpolation {
+	case gltf.InterpolationLinear, gltf.InterpolationStep:
+		if n != len(times) {
+			return fmt.Errorf("gltf: %s interpolation requires one value per keyframe, got %d values for %d keyframes", interpolation, n, len(times))
+		}
+	case gltf.InterpolationCubicSpline:
+		if len(times) < 2 {
+			return errors.New("gltf: CUBICSPLINE interpolation requires at least two keyframes")
+		}
+		if n != keyframeValues(interpolation, len(times)) {
+			return fmt.Errorf("gltf: CUBICSPLINE interpolation requires an in-tangent, a value and an out-tangent per keyframe, got %d values for %d keyframes", n, len(times))
+		}
+	default:
+		return fmt.Errorf("gltf: invalid interpolation %d", interpolation)
+	}
+	return nil
+}
+
+// writeInput writes times as an input accessor, reusing a previous one with the same times.
+func (b *AnimationBuilder) writeInput(times []float32) int {
+	buf := make([]byte, 4*len(times))
+	_ = binary.Write(buf, 0, times)
+	key := string(buf)
+	if index, ok := b.inputs[key]; ok {
+		return index
+	}
+	index := b.writer().WriteAccessor(gltf.TargetNone, times, b.opts...)
+	b.doc.Accessors[index].Min = []float64{float64(times[0])}
+	b.doc.Accessors[index].Max = []float64{float64(times[len(times)-1])}
+	b.inputs[key] = index
+	return index
+}
+
+func (b *AnimationBuilder) writer() *Writer {
+	if b.w != nil {
+		return b.w
+	}
+	return lastWriter(b.doc)
+}
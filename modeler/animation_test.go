@@ -0,0 +1,109 @@
+package modeler_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func TestAnimationBuilder(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{{}, {Mesh: gltf.Index(0)}}
+	doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{Targets: make([]gltf.PrimitiveAttributes, 2)}}}}
+	b := modeler.NewAnimationBuilder(doc, "walk")
+	times := []float32{0, 0.5, 1}
+	if err := b.Translation(0, gltf.InterpolationLinear, times, [][3]float32{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}}); err != nil {
+		t.Fatalf("AnimationBuilder.Translation() error = %v", err)
+	}
+	if err := b.Rotation(0, gltf.InterpolationStep, []float32{0, 0.5, 1}, [][4]float32{{0, 0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 1}}); err != nil {
+		t.Fatalf("AnimationBuilder.Rotation() error = %v", err)
+	}
+	if err := b.Scale(0, gltf.InterpolationCubicSpline, []float32{1, 2}, [][3]float32{{}, {1, 1, 1}, {}, {}, {2, 2, 2}, {}}); err != nil {
+		t.Fatalf("AnimationBuilder.Scale() error = %v", err)
+	}
+	if err := b.Weights(1, gltf.InterpolationLinear, times, []float32{0, 0, 1, 0, 0, 1}); err != nil {
+		t.Fatalf("AnimationBuilder.Weights() error = %v", err)
+	}
+	index, err := b.Build()
+	if err != nil {
+		t.Fatalf("AnimationBuilder.Build() error = %v", err)
+	}
+	want := &gltf.Animation{
+		Name: "walk",
+		Samplers: []*gltf.AnimationSampler{
+			{Input: 0, Output: 1},
+			{Input: 0, Output: 2, Interpolation: gltf.InterpolationStep},
+			{Input: 3, Output: 4, Interpolation: gltf.InterpolationCubicSpline},
+			{Input: 0, Output: 5},
+		},
+		Channels: []*gltf.AnimationChannel{
+			{Sampler: 0, Target: gltf.AnimationChannelTarget{Node: gltf.Index(0), Path: gltf.TRSTranslation}},
+			{Sampler: 1, Target: gltf.AnimationChannelTarget{Node: gltf.Index(0), Path: gltf.TRSRotation}},
+			{Sampler: 2, Target: gltf.AnimationChannelTarget{Node: gltf.Index(0), Path: gltf.TRSScale}},
+			{Sampler: 3, Target: gltf.AnimationChannelTarget{Node: gltf.Index(1), Path: gltf.TRSWeights}},
+		},
+	}
+	if diff := deep.Equal(doc.Animations[index], want); diff != nil {
+		t.Errorf("AnimationBuilder.Build() = %v", diff)
+	}
+	input := doc.Accessors[0]
+	if diff := deep.Equal([][]float64{input.Min, input.Max}, [][]float64{{0}, {1}}); diff != nil {
+		t.Errorf("AnimationBuilder input min max = %v", diff)
+	}
+	if got := doc.Accessors[5].Count; got != 6 {
+		t.Errorf("AnimationBuilder weights count = %d, want 6", got)
+	}
+	if again, err := b.Build(); err != nil || again != index || len(doc.Animations) != 1 {
+		t.Errorf("AnimationBuilder.Build() again = (%d, %v), want (%d, nil) and a single animation", again, err, index)
+	}
+}
+
+func TestAnimationBuilder_Error(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{{}}
+	tests := []struct {
+		name string
+		add  func(b *modeler.AnimationBuilder) error
+	}{
+		{"node", func(b *modeler.AnimationBuilder) error {
+			return b.Translation(1, gltf.InterpolationLinear, []float32{0}, [][3]float32{{}})
+		}},
+		{"empty", func(b *modeler.AnimationBuilder) error {
+			return b.Translation(0, gltf.InterpolationLinear, nil, nil)
+		}},
+		{"unordered", func(b *modeler.AnimationBuilder) error {
+			return b.Translation(0, gltf.InterpolationLinear, []float32{1, 1}, [][3]float32{{}, {}})
+		}},
+		{"negative", func(b *modeler.AnimationBuilder) error {
+			return b.Translation(0, gltf.InterpolationLinear, []float32{-1, 1}, [][3]float32{{}, {}})
+		}},
+		{"linearCount", func(b *modeler.AnimationBuilder) error {
+			return b.Scale(0, gltf.InterpolationLinear, []float32{0, 1}, [][3]float32{{}})
+		}},
+		{"cubicLayout", func(b *modeler.AnimationBuilder) error {
+			return b.Rotation(0, gltf.InterpolationCubicSpline, []float32{0, 1}, [][4]float32{{}, {}})
+		}},
+		{"cubicSingle", func(b *modeler.AnimationBuilder) error {
+			return b.Rotation(0, gltf.InterpolationCubicSpline, []float32{0}, [][4]float32{{}, {}, {}})
+		}},
+		{"weights", func(b *modeler.AnimationBuilder) error {
+			return b.Weights(0, gltf.InterpolationLinear, []float32{0, 1}, []float32{0, 1, 0})
+		}},
+		{"interpolation", func(b *modeler.AnimationBuilder) error {
+			return b.Translation(0, 5, []float32{0}, [][3]float32{{}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := modeler.NewAnimationBuilder(doc, "")
+			if err := tt.add(b); err == nil {
+				t.Error("AnimationBuilder expected error")
+			}
+			if _, err := b.Build(); err == nil {
+				t.Error("AnimationBuilder.Build() expected error without channels")
+			}
+		})
+	}
+}
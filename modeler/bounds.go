@@ -0,0 +1,95 @@
+package modeler
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/qmuntal/gltf"
+)
+
+// A WriteOption configures how accessors are written.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	bounds bool
+}
+
+// WithBounds sets the min and max of the written accessors,
+// as computed by RecomputeBounds.
+func WithBounds() WriteOption {
+	return func(o *writeOptions) {
+		o.bounds = true
+	}
+}
+
+// applyWriteOptions applies opts to the accessor at index.
+func applyWriteOptions(doc *gltf.Document, index int, opts []WriteOption) {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.bounds {
+		// Cannot fail as the accessor has just been written.
+		_ = computeBounds(doc, doc.Accessors[index])
+	}
+}
+
+// RecomputeBounds sets the component-wise min and max of all the accessors in doc.
+//
+// Bounds take into account sparse values and, as required by the glTF spec,
+// normalized integer accessors store the bounds of the integer values.
+// Matrix bounds do not include the column padding.
+func RecomputeBounds(doc *gltf.Document) error {
+	for i, acr := range doc.Accessors {
+		if err := computeBounds(doc, acr); err != nil {
+			return fmt.Errorf("gltf: accessor %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func computeBounds(doc *gltf.Document, acr *gltf.Accessor) error {
+	data, err := ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(data)
+	if v.Len() == 0 {
+		acr.Min, acr.Max = nil, nil
+		return nil
+	}
+	n := acr.Type.Components()
+	min, max := make([]float64, n), make([]float64, n)
+	for i := range min {
+		min[i], max[i] = math.Inf(1), math.Inf(-1)
+	}
+	components := make([]float64, 0, n)
+	for i := 0; i < v.Len(); i++ {
+		components = appendComponents(components[:0], v.Index(i))
+		for j, x := range components {
+			min[j] = math.Min(min[j], x)
+			max[j] = math.Max(max[j], x)
+		}
+	}
+	acr.Min, acr.Max = min, max
+	return nil
+}
+
+// appendComponents appends the components of the scalar, vector or matrix v to dst,
+// matrices being flattened in column-major order.
+func appendComponents(dst []float64, v reflect.Value) []float64 {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dst = appendComponents(dst, v.Index(i))
+		}
+	case reflect.Int8, reflect.Int16:
+		dst = append(dst, float64(v.Int()))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		dst = append(dst, float64(v.Uint()))
+	case reflect.Float32:
+		dst = append(dst, v.Float())
+	}
+	return dst
+}
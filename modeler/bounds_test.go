@@ -0,0 +1,102 @@
+package modeler_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func TestRecomputeBounds(t *testing.T) {
+	doc := gltf.NewDocument()
+	modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, -2, 3}, {-1, 2, 0.5}})
+	modeler.WriteTextureCoord(doc, [][2]uint8{{0, 255}, {10, 20}})
+	modeler.WriteAccessor(doc, gltf.TargetNone, [][2][2]uint8{{{1, 2}, {3, 4}}, {{0, 5}, {6, 1}}})
+	modeler.WriteAccessor(doc, gltf.TargetNone, []int16{-5, 7})
+	modeler.WriteSparseAccessor(doc, nil, [][3]float32{{}, {}, {}, {}, {4, -4, 1}})
+	doc.Accessors = append(doc.Accessors, &gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar, Min: []float64{1}})
+	if err := modeler.RecomputeBounds(doc); err != nil {
+		t.Fatalf("RecomputeBounds() error = %v", err)
+	}
+	want := [][2][]float64{
+		{{-1, -2, 0.5}, {1, 2, 3}},
+		{{0, 20}, {10, 255}},
+		{{0, 2, 3, 1}, {1, 5, 6, 4}},
+		{{-5}, {7}},
+		{{0, -4, 0}, {4, 0, 1}},
+		{nil, nil},
+	}
+	for i, acr := range doc.Accessors {
+		if diff := deep.Equal([2][]float64{acr.Min, acr.Max}, want[i]); diff != nil {
+			t.Errorf("RecomputeBounds() accessor %d = %v", i, diff)
+		}
+	}
+}
+
+func TestRecomputeBounds_Error(t *testing.T) {
+	doc := &gltf.Document{Accessors: []*gltf.Accessor{
+		{BufferView: gltf.Index(0), ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar, Count: 1},
+	}}
+	if err := modeler.RecomputeBounds(doc); err == nil {
+		t.Error("RecomputeBounds() expected error")
+	}
+}
+
+func TestWithBounds(t *testing.T) {
+	doc := gltf.NewDocument()
+	indices := modeler.WriteIndices(doc, []uint16{3, 1, 2}, modeler.WithBounds())
+	weights := modeler.WriteWeights(doc, [][4]float32{{0.5, 0.5, 0, 0}}, modeler.WithBounds())
+	normal := modeler.WriteNormal(doc, [][3]float32{{0, 0, 1}})
+	sparse, _ := modeler.WriteSparseAccessor(doc, nil, []float32{0, 0, 0, 0, 0, 0, 2}, modeler.WithBounds())
+	tests := []struct {
+		index    int
+		min, max []float64
+	}{
+		{indices, []float64{1}, []float64{3}},
+		{weights, []float64{0.5, 0.5, 0, 0}, []float64{0.5, 0.5, 0, 0}},
+		{normal, nil, nil},
+		{sparse, []float64{0}, []float64{2}},
+	}
+	for _, tt := range tests {
+		acr := doc.Accessors[tt.index]
+		if diff := deep.Equal([][]float64{acr.Min, acr.Max}, [][]float64{tt.min, tt.max}); diff != nil {
+			t.Errorf("WithBounds() accessor %d = %v", tt.index, diff)
+		}
+	}
+}
+
+func TestWithBounds_Writer(t *testing.T) {
+	doc := gltf.NewDocument()
+	w := modeler.NewBufferWriter(doc, "", modeler.WithBounds())
+	attrs, err := w.WritePrimitiveAttributes(
+		modeler.PrimitiveAttribute{Name: gltf.NORMAL, Data: [][3]float32{{0, 0, 1}, {0, 1, 0}}},
+		modeler.PrimitiveAttribute{Name: gltf.TEXCOORD_0, Data: [][2]uint8{{0, 255}, {51, 0}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Nodes = append(doc.Nodes, &gltf.Node{})
+	b := modeler.NewAnimationBuilder(doc, "anim", modeler.WithBounds())
+	if err := b.Scale(0, gltf.InterpolationStep, []float32{0, 1}, [][3]float32{{1, 1, 1}, {2, 3, 4}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	output := doc.Animations[0].Samplers[0].Output
+	tests := []struct {
+		index    int
+		min, max []float64
+	}{
+		{attrs[gltf.NORMAL], []float64{0, 0, 0}, []float64{0, 1, 1}},
+		{attrs[gltf.TEXCOORD_0], []float64{0, 0}, []float64{51, 255}},
+		{output, []float64{1, 1, 1}, []float64{2, 3, 4}},
+	}
+	for _, tt := range tests {
+		acr := doc.Accessors[tt.index]
+		if diff := deep.Equal([][]float64{acr.Min, acr.Max}, [][]float64{tt.min, tt.max}); diff != nil {
+			t.Errorf("WithBounds() accessor %d = %v", tt.index, diff)
+		}
+	}
+}
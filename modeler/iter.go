@@ -0,0 +1,150 @@
+package modeler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/binary"
+)
+
+// Iter returns an iterator over the index and value of the elements referenced by acr,
+// decoded on the fly from the buffer view bytes, so no slice with all the elements is allocated.
+// It handles interleaved buffer views, sparse accessors and accessors without buffer view.
+//
+// T must be the element type associated with acr.ComponentType and acr.Type,
+// as returned by ReadAccessor, such as [3]float32 for float VEC3 accessors.
+// Use ReadAs to convert between types.
+//
+// The iterator is a push iterator that, from Go 1.23, can be used with range-over-func:
+//
+//	seq, err := modeler.Iter[[3]float32](doc, acr)
+//	for i, p := range seq {
+//		...
+//	}
+//
+// The accessor is validated before returning the iterator,
+// so it does not fail while iterating. The buffers must not be modified meanwhile.
+func Iter[T any](doc *gltf.Document, acr *gltf.Accessor) (func(yield func(int, T) bool), error) {
+	native, err := binary.MakeSlice(acr.ComponentType, acr.Type, 0)
+	if err != nil {
+		return nil, err
+	}
+	if tp := reflect.TypeOf((*T)(nil)).Elem(); reflect.TypeOf(native).Elem() != tp {
+		return nil, fmt.Errorf("gltf: cannot iterate %s %s accessor as %s", acr.ComponentType, acr.Type, tp)
+	}
+	size := gltf.SizeOfElement(acr.ComponentType, acr.Type)
+	var (
+		data   []byte
+		stride = size
+	)
+	if acr.BufferView != nil {
+		buf, err := readBufferView(doc, *acr.BufferView)
+		if err != nil {
+			return nil, err
+		}
+		if s := doc.BufferViews[*acr.BufferView].ByteStride; s != 0 {
+			stride = s
+		}
+		if data, err = elementBytes(buf, acr.ByteOffset, acr.Count, stride, size); err != nil {
+			return nil, err
+		}
+	}
+	var sparse *sparseIter
+	if acr.Sparse != nil {
+		if sparse, err = newSparseIter(doc, acr, size); err != nil {
+			return nil, err
+		}
+	}
+	return func(yield func(int, T) bool) {
+		// A single element buffer is reused to decode all the elements.
+		one := make([]T, 1)
+		dst := any(one)
+		var zero T
+		k := 0
+		for i := 0; i < acr.Count; i++ {
+			switch {
+			case sparse != nil && k < sparse.count && sparse.index(k) == i:
+				// The input is limited to one element, as binary.Read
+				// decodes some component types until b is exhausted.
+				_ = binary.Read(sparse.values[k*size:(k+1)*size], 0, dst)
+				k++
+			case data != nil:
+				_ = binary.Read(data[i*stride:i*stride+size], 0, dst)
+			default:
+				one[0] = zero
+			}
+			if !yield(i, one[0]) {
+				return
+			}
+		}
+	}, nil
+}
+
+// elementBytes returns the bytes of buf that hold count elements of size bytes
+// separated by stride bytes, starting at offset.
+func elementBytes(buf []byte, offset, count, stride, size int) ([]byte, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	high := offset + (count-1)*stride + size
+	if offset < 0 || high > len(buf) {
+		return nil, io.ErrShortBuffer
+	}
+	return buf[offset:high], nil
+}
+
+// sparseIter reads the sparse indices and values of an accessor without allocating them.
+type sparseIter struct {
+	count   int
+	indices []byte
+	values  []byte
+	c       gltf.ComponentType
+}
+
+func newSparseIter(doc *gltf.Document, acr *gltf.Accessor, size int) (*sparseIter, error) {
+	s := &sparseIter{count: acr.Sparse.Count, c: acr.Sparse.Indices.ComponentType}
+	switch s.c {
+	case gltf.ComponentUbyte, gltf.ComponentUshort, gltf.ComponentUint:
+	default:
+		return nil, errComponentType(s.c)
+	}
+	buf, err := readBufferView(doc, acr.Sparse.Indices.BufferView)
+	if err != nil {
+		return nil, err
+	}
+	indexSize := s.c.ByteSize()
+	if s.indices, err = elementBytes(buf, acr.Sparse.Indices.ByteOffset, s.count, indexSize, indexSize); err != nil {
+		return nil, err
+	}
+	buf, err = readBufferView(doc, acr.Sparse.Values.BufferView)
+	if err != nil {
+		return nil, err
+	}
+	if s.values, err = elementBytes(buf, acr.Sparse.Values.ByteOffset, s.count, size, size); err != nil {
+		return nil, err
+	}
+	// Sparse indices must be strictly increasing, which allows
+	// substituting the values while iterating sequentially.
+	prev := -1
+	for k := 0; k < s.count; k++ {
+		i := s.index(k)
+		if i <= prev || i >= acr.Count {
+			return nil, errors.New("gltf: sparse indices must be strictly increasing and lower than the accessor count")
+		}
+		prev = i
+	}
+	return s, nil
+}
+
+func (s *sparseIter) index(k int) int {
+	switch s.c {
+	case gltf.ComponentUbyte:
+		return int(binary.Ubyte.Scalar(s.indices[k:]))
+	case gltf.ComponentUshort:
+		return int(binary.Ushort.Scalar(s.indices[2*k:]))
+	}
+	return int(binary.Uint.Scalar(s.indices[4*k:]))
+}
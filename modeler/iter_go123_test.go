@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package modeler_test
+
+import (
+	"testing"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func TestIter_RangeFunc(t *testing.T) {
+	doc := gltf.NewDocument()
+	acr := doc.Accessors[modeler.WriteAccessor(doc, gltf.TargetNone, []uint32{1, 2, 3, 4})]
+	seq, err := modeler.Iter[uint32](doc, acr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum uint32
+	for i, v := range seq {
+		if i == 3 {
+			break
+		}
+		sum += v
+	}
+	if sum != 6 {
+		t.Errorf("Iter() sum = %d, want 6", sum)
+	}
+}
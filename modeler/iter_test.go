@@ -0,0 +1,161 @@
+package modeler_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func collect[T any](seq func(yield func(int, T) bool)) []T {
+	var got []T
+	seq(func(i int, v T) bool {
+		got = append(got, v)
+		return true
+	})
+	return got
+}
+
+func TestIter(t *testing.T) {
+	doc := gltf.NewDocument()
+	vec3 := modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, 2, 3}, {4, 5, 6}})
+	interleaved, err := modeler.WriteAccessorsInterleaved(doc, [][3]float32{{1, 2, 3}, {4, 5, 6}}, [][2]uint8{{1, 2}, {3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparse, _ := modeler.WriteSparseAccessor(doc, nil, []uint16{0, 0, 0, 0, 0, 5, 0, 9})
+	doc.Accessors = append(doc.Accessors, &gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorScalar, Count: 3})
+	empty := len(doc.Accessors) - 1
+
+	t.Run("vec3", func(t *testing.T) {
+		seq, err := modeler.Iter[[3]float32](doc, doc.Accessors[vec3])
+		if got := collect(seq); err != nil || !reflect.DeepEqual(got, [][3]float32{{1, 2, 3}, {4, 5, 6}}) {
+			t.Errorf("Iter() = %v, %v", got, err)
+		}
+	})
+	t.Run("interleaved", func(t *testing.T) {
+		seq, err := modeler.Iter[[2]uint8](doc, doc.Accessors[interleaved[1]])
+		if got := collect(seq); err != nil || !reflect.DeepEqual(got, [][2]uint8{{1, 2}, {3, 4}}) {
+			t.Errorf("Iter() = %v, %v", got, err)
+		}
+	})
+	t.Run("sparse", func(t *testing.T) {
+		seq, err := modeler.Iter[uint16](doc, doc.Accessors[sparse])
+		if got := collect(seq); err != nil || !reflect.DeepEqual(got, []uint16{0, 0, 0, 0, 0, 5, 0, 9}) {
+			t.Errorf("Iter() = %v, %v", got, err)
+		}
+	})
+	t.Run("withoutBufferView", func(t *testing.T) {
+		seq, err := modeler.Iter[float32](doc, doc.Accessors[empty])
+		if got := collect(seq); err != nil || !reflect.DeepEqual(got, []float32{0, 0, 0}) {
+			t.Errorf("Iter() = %v, %v", got, err)
+		}
+	})
+	t.Run("break", func(t *testing.T) {
+		seq, err := modeler.Iter[[3]float32](doc, doc.Accessors[vec3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		seq(func(i int, v [3]float32) bool {
+			n++
+			return false
+		})
+		if n != 1 {
+			t.Errorf("Iter() yielded %d elements after break", n)
+		}
+	})
+	errTests := []struct {
+		name string
+		acr  *gltf.Accessor
+	}{
+		{"type", &gltf.Accessor{BufferView: gltf.Index(0), ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec2, Count: 2}},
+		{"bufferView", &gltf.Accessor{BufferView: gltf.Index(10), ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 2}},
+		{"count", &gltf.Accessor{BufferView: gltf.Index(0), ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3}},
+		{"sparseOrder", &gltf.Accessor{ComponentType: gltf.ComponentFloat, Type: gltf.AccessorVec3, Count: 3, Sparse: &gltf.Sparse{
+			Count:   2,
+			Indices: gltf.SparseIndices{BufferView: 0, ComponentType: gltf.ComponentUbyte},
+			Values:  gltf.SparseValues{BufferView: 0},
+		}}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := modeler.Iter[[3]float32](doc, tt.acr); err == nil {
+				t.Error("Iter() expected error")
+			}
+		})
+	}
+}
+
+func TestIterAllocs(t *testing.T) {
+	doc := gltf.NewDocument()
+	data := make([][3]float32, 1000)
+	acr := doc.Accessors[modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, data)]
+	var sum float32
+	allocs := testing.AllocsPerRun(50, func() {
+		seq, _ := modeler.Iter[[3]float32](doc, acr)
+		seq(func(i int, v [3]float32) bool {
+			sum += v[0]
+			return true
+		})
+	})
+	if allocs > 3 {
+		t.Errorf("Iter expected at most 3 allocs got %v", allocs)
+	}
+}
+
+func TestIter_Scalar(t *testing.T) {
+	doc := gltf.NewDocument()
+	tests := []struct {
+		name string
+		iter func() (any, error)
+		want any
+	}{
+		{"int8", iterAll(doc, []int8{1, -2, 3}), []int8{1, -2, 3}},
+		{"uint8", iterAll(doc, []uint8{1, 2, 3}), []uint8{1, 2, 3}},
+		{"int16", iterAll(doc, []int16{1, -2, 3}), []int16{1, -2, 3}},
+		{"uint16", iterAll(doc, []uint16{1, 2, 3}), []uint16{1, 2, 3}},
+		{"uint32", iterAll(doc, []uint32{1, 2, 3}), []uint32{1, 2, 3}},
+		{"float32", iterAll(doc, []float32{1, -2, 3}), []float32{1, -2, 3}},
+		{"sparseInt8", iterSparse(doc, []int8{0, 0, 0, 0, 0, 0, -7, 0}), []int8{0, 0, 0, 0, 0, 0, -7, 0}},
+		{"sparseUint8", iterSparse(doc, []uint8{0, 0, 0, 0, 0, 0, 7, 0}), []uint8{0, 0, 0, 0, 0, 0, 7, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.iter()
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Iter() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func iterAll[T any](doc *gltf.Document, data []T) func() (any, error) {
+	index := modeler.WriteAccessor(doc, gltf.TargetNone, data)
+	return func() (any, error) {
+		seq, err := modeler.Iter[T](doc, doc.Accessors[index])
+		if err != nil {
+			return nil, err
+		}
+		return collect(seq), nil
+	}
+}
+
+func iterSparse[T any](doc *gltf.Document, data []T) func() (any, error) {
+	index, err := modeler.WriteSparseAccessor(doc, nil, data)
+	return func() (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		if doc.Accessors[index].Sparse == nil {
+			return nil, errors.New("accessor is not sparse")
+		}
+		seq, err := modeler.Iter[T](doc, doc.Accessors[index])
+		if err != nil {
+			return nil, err
+		}
+		return collect(seq), nil
+	}
+}
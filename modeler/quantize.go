@@ -0,0 +1,360 @@
+package modeler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/meshquantization"
+)
+
+// QuantizeOptions defines the component types used by WriteQuantizedAttributes
+// to quantize the primitive attributes, as allowed by KHR_mesh_quantization.
+// All the quantized attributes are normalized.
+//
+// gltf.ComponentFloat, the zero value, keeps the attribute as float.
+type QuantizeOptions struct {
+	Position     gltf.ComponentType // Byte, Ubyte, Short or Ushort.
+	Normal       gltf.ComponentType // Byte or Short.
+	Tangent      gltf.ComponentType // Byte or Short.
+	TextureCoord gltf.ComponentType // Ubyte or Ushort, applied to all the TEXCOORD_n sets.
+}
+
+// A Dequantization is the transform that restores quantized positions
+// to their original values. It must be applied, using Apply,
+// to the node that instantiates the mesh.
+//
+// The scale is uniform so normals and tangents are not distorted.
+type Dequantization struct {
+	Translation [3]float64
+	Scale       [3]float64
+}
+
+// Apply composes d with the local transform of node, which must instantiate
+// the mesh with the quantized positions, so its mesh is rendered
+// with the original positions in the same place as before quantization.
+//
+// If node uses a matrix it is multiplied by d, else d is composed
+// with its translation and scale.
+func (d Dequantization) Apply(node *gltf.Node) {
+	if node.Matrix != gltf.DefaultMatrix && node.Matrix != ([16]float64{}) {
+		m := node.Matrix
+		for i := 0; i < 3; i++ {
+			node.Matrix[12+i] = m[12+i] + m[i]*d.Translation[0] + m[4+i]*d.Translation[1] + m[8+i]*d.Translation[2]
+			for j := 0; j < 3; j++ {
+				node.Matrix[j*4+i] = m[j*4+i] * d.Scale[j]
+			}
+		}
+		return
+	}
+	scale := node.ScaleOrDefault()
+	// The scale of d is uniform, so it commutes with the rotation of node.
+	t := rotate(node.RotationOrDefault(), [3]float64{
+		scale[0] * d.Translation[0], scale[1] * d.Translation[1], scale[2] * d.Translation[2],
+	})
+	for i := range t {
+		node.Translation[i] += t[i]
+		scale[i] *= d.Scale[i]
+	}
+	node.Scale = scale
+}
+
+// rotate returns v rotated by the unit quaternion q.
+func rotate(q [4]float64, v [3]float64) [3]float64 {
+	cross := func(a, b [3]float64) [3]float64 {
+		return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+	}
+	u := [3]float64{q[0], q[1], q[2]}
+	t := cross(u, v)
+	t = [3]float64{2 * t[0], 2 * t[1], 2 * t[2]}
+	c := cross(u, t)
+	return [3]float64{v[0] + q[3]*t[0] + c[0], v[1] + q[3]*t[1] + c[1], v[2] + q[3]*t[2] + c[2]}
+}
+
+// WriteQuantizedAttributes is like Writer.WriteQuantizedAttributes but writes into the last buffer of doc.
+func WriteQuantizedAttributes(doc *gltf.Document, opts QuantizeOptions, attr ...PrimitiveAttribute) (gltf.PrimitiveAttributes, Dequantization, error) {
+	return lastWriter(doc).WriteQuantizedAttributes(opts, attr...)
+}
+
+// WriteQuantizedAttributes is like WritePrimitiveAttributes but quantizes the
+// POSITION, NORMAL, TANGENT and TEXCOORD_n attributes as defined by opts.
+// Quantized attributes are written as non-interleaved accessors.
+//
+// Positions are mapped to the full range of their component type,
+// the returned Dequantization restores them when applied to the mesh node.
+// If positions are not quantized it is the identity transform.
+//
+// If any attribute is quantized, KHR_mesh_quantization is added
+// to the ExtensionsUsed and ExtensionsRequired of the document.
+// Texture coordinates must be in the [0, 1] range.
+func (w *Writer) WriteQuantizedAttributes(opts QuantizeOptions, attr ...PrimitiveAttribute) (gltf.PrimitiveAttributes, Dequantization, error) {
+	deq := Dequantization{Scale: gltf.DefaultScale}
+	quantized := make(map[string]any)
+	var others []PrimitiveAttribute
+	for _, a := range attr {
+		if sliceLength(a.Data) == 0 {
+			continue
+		}
+		var (
+			data any
+			err  error
+		)
+		switch {
+		case a.Name == gltf.POSITION && opts.Position != gltf.ComponentFloat:
+			data, deq, err = quantizePosition(a.Data, opts.Position)
+		case a.Name == gltf.NORMAL && opts.Normal != gltf.ComponentFloat:
+			data, err = quantizeDirection(a.Data, opts.Normal)
+		case a.Name == gltf.TANGENT && opts.Tangent != gltf.ComponentFloat:
+			data, err = quantizeTangent(a.Data, opts.Tangent)
+		case strings.HasPrefix(a.Name, "TEXCOORD_") && opts.TextureCoord != gltf.ComponentFloat:
+			data, err = quantizeTextureCoord(a.Data, opts.TextureCoord)
+		default:
+			others = append(others, a)
+			continue
+		}
+		if err != nil {
+			return nil, deq, fmt.Errorf("%s: %w", a.Name, err)
+		}
+		quantized[a.Name] = data
+	}
+	attrs := make(gltf.PrimitiveAttributes)
+	if len(others) > 0 {
+		var err error
+		if attrs, err = w.WritePrimitiveAttributes(others...); err != nil {
+			return nil, deq, err
+		}
+	}
+	for _, a := range attr {
+		data, ok := quantized[a.Name]
+		if !ok {
+			continue
+		}
+		index := w.WriteAccessor(gltf.TargetArrayBuffer, data)
+		w.doc.Accessors[index].Normalized = true
+		if a.Name == gltf.POSITION {
+			w.doc.Accessors[index].Min, w.doc.Accessors[index].Max = minMaxQuantized(data)
+		}
+		attrs[a.Name] = index
+	}
+	if len(quantized) > 0 {
+		addExtension(&w.doc.ExtensionsUsed, meshquantization.ExtensionName)
+		addExtension(&w.doc.ExtensionsRequired, meshquantization.ExtensionName)
+	}
+	return attrs, deq, nil
+}
+
+func quantizePosition(data any, c gltf.ComponentType) (any, Dequantization, error) {
+	var deq Dequantization
+	v, ok := data.([][3]float32)
+	if !ok {
+		return nil, deq, fmt.Errorf("invalid type %T", data)
+	}
+	min, max := minMaxFloat32(v)
+	var extent float64
+	for i := range min {
+		extent = math.Max(extent, max[i]-min[i])
+	}
+	if extent == 0 {
+		extent = 1
+	}
+	signed := c == gltf.ComponentByte || c == gltf.ComponentShort
+	for i := range min {
+		if signed {
+			// Signed components map the positions to [-1, 1] around the center.
+			deq.Translation[i] = (min[i] + max[i]) / 2
+		} else {
+			deq.Translation[i] = min[i]
+		}
+	}
+	scale := extent
+	if signed {
+		scale /= 2
+	}
+	deq.Scale = [3]float64{scale, scale, scale}
+	normalized := make([][3]float32, len(v))
+	for i, p := range v {
+		for j, x := range p {
+			normalized[i][j] = float32((float64(x) - deq.Translation[j]) / scale)
+		}
+	}
+	var q any
+	switch c {
+	case gltf.ComponentByte:
+		q = quantizeVec3(normalized, gltf.NormalizeByte)
+	case gltf.ComponentUbyte:
+		q = quantizeVec3(normalized, gltf.NormalizeUbyte)
+	case gltf.ComponentShort:
+		q = quantizeVec3(normalized, gltf.NormalizeShort)
+	case gltf.ComponentUshort:
+		q = quantizeVec3(normalized, gltf.NormalizeUshort)
+	default:
+		return nil, deq, errComponentType(c)
+	}
+	return q, deq, nil
+}
+
+func quantizeDirection(data any, c gltf.ComponentType) (any, error) {
+	v, ok := data.([][3]float32)
+	if !ok {
+		return nil, fmt.Errorf("invalid type %T", data)
+	}
+	switch c {
+	case gltf.ComponentByte:
+		return quantizeVec3(v, gltf.NormalizeByte), nil
+	case gltf.ComponentShort:
+		return quantizeVec3(v, gltf.NormalizeShort), nil
+	}
+	return nil, errComponentType(c)
+}
+
+func quantizeTangent(data any, c gltf.ComponentType) (any, error) {
+	v, ok := data.([][4]float32)
+	if !ok {
+		return nil, fmt.Errorf("invalid type %T", data)
+	}
+	switch c {
+	case gltf.ComponentByte:
+		return quantizeVec4(v, gltf.NormalizeByte), nil
+	case gltf.ComponentShort:
+		return quantizeVec4(v, gltf.NormalizeShort), nil
+	}
+	return nil, errComponentType(c)
+}
+
+func quantizeTextureCoord(data any, c gltf.ComponentType) (any, error) {
+	v, ok := data.([][2]float32)
+	if !ok {
+		return nil, fmt.Errorf("invalid type %T", data)
+	}
+	for _, e := range v {
+		if e[0] < 0 || e[0] > 1 || e[1] < 0 || e[1] > 1 {
+			return nil, fmt.Errorf("texture coordinate %v out of the [0, 1] range", e)
+		}
+	}
+	switch c {
+	case gltf.ComponentUbyte:
+		return quantizeVec2(v, gltf.NormalizeUbyte), nil
+	case gltf.ComponentUshort:
+		return quantizeVec2(v, gltf.NormalizeUshort), nil
+	}
+	return nil, errComponentType(c)
+}
+
+// minMaxQuantized returns the bounds of quantized positions,
+// which are expressed in the component type as required by the glTF spec.
+func minMaxQuantized(data any) ([]float64, []float64) {
+	switch data := data.(type) {
+	case [][3]int8:
+		return minMaxVec3(data)
+	case [][3]uint8:
+		return minMaxVec3(data)
+	case [][3]int16:
+		return minMaxVec3(data)
+	case [][3]uint16:
+		return minMaxVec3(data)
+	}
+	return nil, nil
+}
+
+func minMaxVec3[T quantizedComponent](data [][3]T) ([]float64, []float64) {
+	min := []float64{math.MaxFloat64, math.MaxFloat64, math.MaxFloat64}
+	max := []float64{-math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64}
+	for _, v := range data {
+		for i, x := range v {
+			min[i] = math.Min(min[i], float64(x))
+			max[i] = math.Max(max[i], float64(x))
+		}
+	}
+	return min, max
+}
+
+func addExtension(list *[]string, name string) {
+	for _, e := range *list {
+		if e == name {
+			return
+		}
+	}
+	*list = append(*list, name)
+}
+
+// checkQuantizedType returns an error if c is neither float
+// nor one of the quantized component types, which are allowed by KHR_mesh_quantization.
+func checkQuantizedType(c gltf.ComponentType, quantized ...gltf.ComponentType) error {
+	if c == gltf.ComponentFloat {
+		return nil
+	}
+	for _, q := range quantized {
+		if c == q {
+			return nil
+		}
+	}
+	return errComponentType(c)
+}
+
+type quantizedComponent interface {
+	int8 | uint8 | int16 | uint16
+}
+
+func quantizeVec2[T quantizedComponent](data [][2]float32, normalize func(float32) T) [][2]T {
+	q := make([][2]T, len(data))
+	for i, e := range data {
+		q[i] = [2]T{normalize(e[0]), normalize(e[1])}
+	}
+	return q
+}
+
+func quantizeVec3[T quantizedComponent](data [][3]float32, normalize func(float32) T) [][3]T {
+	q := make([][3]T, len(data))
+	for i, e := range data {
+		q[i] = [3]T{normalize(e[0]), normalize(e[1]), normalize(e[2])}
+	}
+	return q
+}
+
+func quantizeVec4[T quantizedComponent](data [][4]float32, normalize func(float32) T) [][4]T {
+	q := make([][4]T, len(data))
+	for i, e := range data {
+		q[i] = [4]T{normalize(e[0]), normalize(e[1]), normalize(e[2]), normalize(e[3])}
+	}
+	return q
+}
+
+// dequantize converts v to float32, denormalizing it if normalized is true.
+func dequantize[T quantizedComponent](v T, normalized bool) float32 {
+	if !normalized {
+		return float32(v)
+	}
+	switch v := any(v).(type) {
+	case int8:
+		return gltf.DenormalizeByte(v)
+	case uint8:
+		return gltf.DenormalizeUbyte(v)
+	case int16:
+		return gltf.DenormalizeShort(v)
+	case uint16:
+		return gltf.DenormalizeUshort(v)
+	}
+	return 0
+}
+
+func dequantizeVec2[T quantizedComponent](buffer [][2]float32, data [][2]T, normalized bool) {
+	for i, e := range data {
+		buffer[i] = [2]float32{dequantize(e[0], normalized), dequantize(e[1], normalized)}
+	}
+}
+
+func dequantizeVec3[T quantizedComponent](buffer [][3]float32, data [][3]T, normalized bool) {
+	for i, e := range data {
+		buffer[i] = [3]float32{dequantize(e[0], normalized), dequantize(e[1], normalized), dequantize(e[2], normalized)}
+	}
+}
+
+func dequantizeVec4[T quantizedComponent](buffer [][4]float32, data [][4]T, normalized bool) {
+	for i, e := range data {
+		buffer[i] = [4]float32{
+			dequantize(e[0], normalized), dequantize(e[1], normalized),
+			dequantize(e[2], normalized), dequantize(e[3], normalized),
+		}
+	}
+}
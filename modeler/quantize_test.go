@@ -0,0 +1,156 @@
+package modeler_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/ext/meshquantization"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func TestWriteQuantizedAttributes(t *testing.T) {
+	positions := [][3]float32{{-1, 0, 2}, {3, 2, 4}, {1, 1, 3}}
+	normals := [][3]float32{{0, 0, 1}, {0, 1, 0}, {-1, 0, 0}}
+	tangents := [][4]float32{{1, 0, 0, 1}, {1, 0, 0, -1}, {0, 1, 0, 1}}
+	uvs := [][2]float32{{0, 1}, {0.5, 0.5}, {1, 0}}
+	for _, c := range []gltf.ComponentType{gltf.ComponentByte, gltf.ComponentUbyte, gltf.ComponentShort, gltf.ComponentUshort} {
+		t.Run(c.String(), func(t *testing.T) {
+			doc := gltf.NewDocument()
+			dirType, uvType := gltf.ComponentShort, gltf.ComponentUshort
+			if c == gltf.ComponentByte || c == gltf.ComponentUbyte {
+				dirType, uvType = gltf.ComponentByte, gltf.ComponentUbyte
+			}
+			attrs, deq, err := modeler.WriteQuantizedAttributes(doc, modeler.QuantizeOptions{
+				Position: c, Normal: dirType, Tangent: dirType, TextureCoord: uvType,
+			},
+				modeler.PrimitiveAttribute{Name: gltf.POSITION, Data: positions},
+				modeler.PrimitiveAttribute{Name: gltf.NORMAL, Data: normals},
+				modeler.PrimitiveAttribute{Name: gltf.TANGENT, Data: tangents},
+				modeler.PrimitiveAttribute{Name: gltf.TEXCOORD_0, Data: uvs},
+				modeler.PrimitiveAttribute{Name: gltf.COLOR_0, Data: [][3]uint8{{1, 2, 3}, {1, 2, 3}, {1, 2, 3}}},
+			)
+			if err != nil {
+				t.Fatalf("WriteQuantizedAttributes() error = %v", err)
+			}
+			if diff := deep.Equal(doc.ExtensionsRequired, []string{meshquantization.ExtensionName}); diff != nil {
+				t.Errorf("WriteQuantizedAttributes() extensionsRequired = %v", diff)
+			}
+			if doc.Accessors[attrs[gltf.POSITION]].ComponentType != c || !doc.Accessors[attrs[gltf.POSITION]].Normalized {
+				t.Errorf("WriteQuantizedAttributes() position accessor = %+v", doc.Accessors[attrs[gltf.POSITION]])
+			}
+			data, err := modeler.ReadPrimitive(doc, &gltf.Primitive{Attributes: attrs})
+			if err != nil {
+				t.Fatalf("ReadPrimitive() error = %v", err)
+			}
+			tolerance := 1.0 / 100
+			if c == gltf.ComponentShort || c == gltf.ComponentUshort {
+				tolerance = 1.0 / 10000
+			}
+			for i, p := range data.Position {
+				for j, x := range p {
+					got := float64(x)*deq.Scale[j] + deq.Translation[j]
+					if math.Abs(got-float64(positions[i][j])) > 4*tolerance {
+						t.Errorf("position %d = %v, want %v", i, got, positions[i][j])
+					}
+				}
+				for j, x := range data.Normal[i] {
+					if math.Abs(float64(x-normals[i][j])) > tolerance {
+						t.Errorf("normal %d = %v, want %v", i, data.Normal[i], normals[i])
+					}
+				}
+				for j, x := range data.Tangent[i] {
+					if math.Abs(float64(x-tangents[i][j])) > tolerance {
+						t.Errorf("tangent %d = %v, want %v", i, data.Tangent[i], tangents[i])
+					}
+				}
+				for j, x := range data.TextureCoords[0][i] {
+					if math.Abs(float64(x-uvs[i][j])) > tolerance {
+						t.Errorf("texture coordinate %d = %v, want %v", i, data.TextureCoords[0][i], uvs[i])
+					}
+				}
+			}
+			if deq.Scale[0] != deq.Scale[1] || deq.Scale[0] != deq.Scale[2] {
+				t.Errorf("WriteQuantizedAttributes() scale = %v, want uniform", deq.Scale)
+			}
+		})
+	}
+}
+
+func TestWriteQuantizedAttributes_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		opts modeler.QuantizeOptions
+		attr modeler.PrimitiveAttribute
+	}{
+		{"position", modeler.QuantizeOptions{Position: gltf.ComponentUint}, modeler.PrimitiveAttribute{Name: gltf.POSITION, Data: [][3]float32{{}}}},
+		{"normal", modeler.QuantizeOptions{Normal: gltf.ComponentUbyte}, modeler.PrimitiveAttribute{Name: gltf.NORMAL, Data: [][3]float32{{}}}},
+		{"tangentType", modeler.QuantizeOptions{Tangent: gltf.ComponentByte}, modeler.PrimitiveAttribute{Name: gltf.TANGENT, Data: [][3]float32{{}}}},
+		{"uvRange", modeler.QuantizeOptions{TextureCoord: gltf.ComponentUbyte}, modeler.PrimitiveAttribute{Name: gltf.TEXCOORD_0, Data: [][2]float32{{2, 0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			if _, _, err := modeler.WriteQuantizedAttributes(doc, tt.opts, tt.attr); err == nil {
+				t.Error("WriteQuantizedAttributes() expected error")
+			}
+		})
+	}
+}
+
+func TestReadPosition_Quantized(t *testing.T) {
+	doc := &gltf.Document{
+		Buffers:     []*gltf.Buffer{{ByteLength: 8, Data: []byte{255, 127, 1, 0, 0, 128, 0, 0}}},
+		BufferViews: []*gltf.BufferView{{ByteLength: 8}},
+	}
+	acr := &gltf.Accessor{BufferView: gltf.Index(0), Count: 1, Type: gltf.AccessorVec3, ComponentType: gltf.ComponentShort}
+	// The accessor type is enough, KHR_mesh_quantization is not checked.
+	got, err := modeler.ReadPosition(doc, acr, nil)
+	if err != nil {
+		t.Fatalf("ReadPosition() error = %v", err)
+	}
+	if want := [][3]float32{{32767, 1, -32768}}; deep.Equal(got, want) != nil {
+		t.Errorf("ReadPosition() = %v, want %v", got, want)
+	}
+	acr.Normalized = true
+	got, _ = modeler.ReadPosition(doc, acr, nil)
+	if want := [][3]float32{{1, 1.0 / 32767, -1}}; deep.Equal(got, want) != nil {
+		t.Errorf("ReadPosition() = %v, want %v", got, want)
+	}
+	uv, _ := modeler.ReadTextureCoord(doc, &gltf.Accessor{BufferView: gltf.Index(0), Count: 1, Type: gltf.AccessorVec2, ComponentType: gltf.ComponentByte}, nil)
+	if want := [][2]float32{{-1, 127}}; deep.Equal(uv, want) != nil {
+		t.Errorf("ReadTextureCoord() = %v, want %v", uv, want)
+	}
+}
+
+func TestDequantization_Apply(t *testing.T) {
+	deq := modeler.Dequantization{Translation: [3]float64{1, 2, 3}, Scale: [3]float64{2, 2, 2}}
+	tests := []struct {
+		name string
+		node gltf.Node
+		want gltf.Node
+	}{
+		{"empty", gltf.Node{}, gltf.Node{Translation: [3]float64{1, 2, 3}, Scale: [3]float64{2, 2, 2}}},
+		{"default", gltf.Node{Matrix: gltf.DefaultMatrix, Rotation: gltf.DefaultRotation, Scale: gltf.DefaultScale}, gltf.Node{
+			Matrix: gltf.DefaultMatrix, Rotation: gltf.DefaultRotation, Translation: [3]float64{1, 2, 3}, Scale: [3]float64{2, 2, 2},
+		}},
+		{"scaled", gltf.Node{Translation: [3]float64{10, 0, 0}, Scale: [3]float64{2, 3, 4}}, gltf.Node{
+			Translation: [3]float64{12, 6, 12}, Scale: [3]float64{4, 6, 8},
+		}},
+		{"rotated", gltf.Node{Translation: [3]float64{10, 0, 0}, Rotation: [4]float64{0, 0, math.Sqrt2 / 2, math.Sqrt2 / 2}}, gltf.Node{
+			Translation: [3]float64{8, 1, 3}, Rotation: [4]float64{0, 0, math.Sqrt2 / 2, math.Sqrt2 / 2}, Scale: [3]float64{2, 2, 2},
+		}},
+		{"matrix", gltf.Node{Matrix: [16]float64{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 10, 0, 0, 1}}, gltf.Node{
+			Matrix: [16]float64{4, 0, 0, 0, 0, 4, 0, 0, 0, 0, 4, 0, 12, 4, 6, 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deq.Apply(&tt.node)
+			if diff := deep.Equal(tt.node, tt.want); diff != nil {
+				t.Errorf("Dequantization.Apply() = %v", diff)
+			}
+		})
+	}
+}
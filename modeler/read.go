@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/qmuntal/gltf"
@@ -29,6 +32,18 @@ var uint32Pool = sync.Pool{
 // ReadAccessor is safe to use even with malformed documents.
 // If that happens it will return an error instead of panic.
 func ReadAccessor(doc *gltf.Document, acr *gltf.Accessor, buffer []byte) (any, error) {
+	if acr.BufferView == nil && buffer != nil {
+		// Accessors without buffer view are initialized with zeros,
+		// so the backing slice can't have stale data.
+		n := acr.Count * gltf.SizeOfElement(acr.ComponentType, acr.Type)
+		if n < 0 || n > cap(buffer) {
+			n = cap(buffer)
+		}
+		buffer = buffer[:n]
+		for i := range buffer {
+			buffer[i] = 0
+		}
+	}
 	data, err := binary.MakeSliceBuffer(acr.ComponentType, acr.Type, acr.Count, buffer)
 	if err != nil {
 		return nil, err
@@ -161,11 +176,13 @@ func ReadIndices(doc *gltf.Document, acr *gltf.Accessor, buffer []uint32) ([]uin
 }
 
 // ReadNormal returns the data referenced by acr.
+// Byte and short normals, allowed by KHR_mesh_quantization,
+// will be converted and denormalized if acr is normalized.
 //
 // See ReadAccessor for more info.
 func ReadNormal(doc *gltf.Document, acr *gltf.Accessor, buffer [][3]float32) ([][3]float32, error) {
-	if acr.ComponentType != gltf.ComponentFloat {
-		return nil, errComponentType(acr.ComponentType)
+	if err := checkQuantizedType(acr.ComponentType, gltf.ComponentByte, gltf.ComponentShort); err != nil {
+		return nil, err
 	}
 	if acr.Type != gltf.AccessorVec3 {
 		return nil, errAccessorType(acr.Type)
@@ -177,16 +194,18 @@ func ReadNormal(doc *gltf.Document, acr *gltf.Accessor, buffer [][3]float32) ([]
 		return nil, err
 	}
 	buffer = makeBufferOf(acr.Count, buffer)
-	copy(buffer, data.([][3]float32))
+	readVec3(buffer, data, acr.Normalized)
 	return buffer, nil
 }
 
 // ReadTangent returns the data referenced by acr.
+// Byte and short tangents, allowed by KHR_mesh_quantization,
+// will be converted and denormalized if acr is normalized.
 //
 // See ReadAccessor for more info.
 func ReadTangent(doc *gltf.Document, acr *gltf.Accessor, buffer [][4]float32) ([][4]float32, error) {
-	if acr.ComponentType != gltf.ComponentFloat {
-		return nil, errComponentType(acr.ComponentType)
+	if err := checkQuantizedType(acr.ComponentType, gltf.ComponentByte, gltf.ComponentShort); err != nil {
+		return nil, err
 	}
 	if acr.Type != gltf.AccessorVec4 {
 		return nil, errAccessorType(acr.Type)
@@ -198,7 +217,14 @@ func ReadTangent(doc *gltf.Document, acr *gltf.Accessor, buffer [][4]float32) ([
 		return nil, err
 	}
 	buffer = makeBufferOf(acr.Count, buffer)
-	copy(buffer, data.([][4]float32))
+	switch data := data.(type) {
+	case [][4]int8:
+		dequantizeVec4(buffer, data, acr.Normalized)
+	case [][4]int16:
+		dequantizeVec4(buffer, data, acr.Normalized)
+	case [][4]float32:
+		copy(buffer, data)
+	}
 	return buffer, nil
 }
 
@@ -206,12 +232,14 @@ func ReadTangent(doc *gltf.Document, acr *gltf.Accessor, buffer [][4]float32) ([
 // If acr.ComponentType is other than Float the data
 // will be converted and denormalized appropriately.
 //
+// Unsigned byte and short texture coordinates are always denormalized,
+// as required by the glTF spec. Byte and short texture coordinates,
+// allowed by KHR_mesh_quantization, are denormalized if acr is normalized.
+//
 // See ReadAccessor for more info.
 func ReadTextureCoord(doc *gltf.Document, acr *gltf.Accessor, buffer [][2]float32) ([][2]float32, error) {
-	switch acr.ComponentType {
-	case gltf.ComponentUbyte, gltf.ComponentUshort, gltf.ComponentFloat:
-	default:
-		return nil, errComponentType(acr.ComponentType)
+	if err := checkQuantizedType(acr.ComponentType, gltf.ComponentByte, gltf.ComponentUbyte, gltf.ComponentShort, gltf.ComponentUshort); err != nil {
+		return nil, err
 	}
 	if acr.Type != gltf.AccessorVec2 {
 		return nil, errAccessorType(acr.Type)
@@ -224,18 +252,14 @@ func ReadTextureCoord(doc *gltf.Document, acr *gltf.Accessor, buffer [][2]float3
 	}
 	buffer = makeBufferOf(acr.Count, buffer)
 	switch data := data.(type) {
+	case [][2]int8:
+		dequantizeVec2(buffer, data, acr.Normalized)
 	case [][2]uint8:
-		for i, e := range data {
-			buffer[i] = [2]float32{
-				gltf.DenormalizeUbyte(e[0]), gltf.DenormalizeUbyte(e[1]),
-			}
-		}
+		dequantizeVec2(buffer, data, true)
+	case [][2]int16:
+		dequantizeVec2(buffer, data, acr.Normalized)
 	case [][2]uint16:
-		for i, e := range data {
-			buffer[i] = [2]float32{
-				gltf.DenormalizeUshort(e[0]), gltf.DenormalizeUshort(e[1]),
-			}
-		}
+		dequantizeVec2(buffer, data, true)
 	case [][2]float32:
 		copy(buffer, data)
 	}
@@ -320,11 +344,14 @@ func ReadJoints(doc *gltf.Document, acr *gltf.Accessor, buffer [][4]uint16) ([][
 }
 
 // ReadPosition returns the data referenced by acr.
+// Byte, unsigned byte, short and unsigned short positions,
+// allowed by KHR_mesh_quantization, will be converted and
+// denormalized if acr is normalized.
 //
 // See ReadAccessor for more info.
 func ReadPosition(doc *gltf.Document, acr *gltf.Accessor, buffer [][3]float32) ([][3]float32, error) {
-	if acr.ComponentType != gltf.ComponentFloat {
-		return nil, errComponentType(acr.ComponentType)
+	if err := checkQuantizedType(acr.ComponentType, gltf.ComponentByte, gltf.ComponentUbyte, gltf.ComponentShort, gltf.ComponentUshort); err != nil {
+		return nil, err
 	}
 	if acr.Type != gltf.AccessorVec3 {
 		return nil, errAccessorType(acr.Type)
@@ -336,10 +363,26 @@ func ReadPosition(doc *gltf.Document, acr *gltf.Accessor, buffer [][3]float32) (
 		return nil, err
 	}
 	buffer = makeBufferOf(acr.Count, buffer)
-	copy(buffer, data.([][3]float32))
+	readVec3(buffer, data, acr.Normalized)
 	return buffer, nil
 }
 
+// readVec3 converts the float or quantized data into buffer.
+func readVec3(buffer [][3]float32, data any, normalized bool) {
+	switch data := data.(type) {
+	case [][3]int8:
+		dequantizeVec3(buffer, data, normalized)
+	case [][3]uint8:
+		dequantizeVec3(buffer, data, normalized)
+	case [][3]int16:
+		dequantizeVec3(buffer, data, normalized)
+	case [][3]uint16:
+		dequantizeVec3(buffer, data, normalized)
+	case [][3]float32:
+		copy(buffer, data)
+	}
+}
+
 // ReadColor returns the data referenced by acr.
 // If acr.ComponentType is other than Ubyte the data
 // will be converted and normalized appropriately.
@@ -473,6 +516,289 @@ func ReadInverseBindMatrices(doc *gltf.Document, acr *gltf.Accessor, buffer [][4
 	return buffer, nil
 }
 
+// PrimitiveData holds the decoded attributes of a primitive.
+// Attributes not defined by the primitive are nil.
+//
+// The sets of TEXCOORD_n, COLOR_n, JOINTS_n and WEIGHTS_n attributes
+// are stored at index n of their slices, ReadPrimitive fails if they are not contiguous.
+// Colors are read with ReadColor, so float and unsigned short colors are narrowed to 8 bits.
+type PrimitiveData struct {
+	Indices       []uint32
+	Position      [][3]float32
+	Normal        [][3]float32
+	Tangent       [][4]float32
+	TextureCoords [][][2]float32
+	Colors        [][][4]uint8
+	Joints        [][][4]uint16
+	Weights       [][][4]float32
+	Targets       []MorphTarget
+}
+
+// ReadPrimitive returns all the attributes, indices and morph targets of prim.
+// If prim is not indexed, sequential indices are generated for its vertices.
+//
+// See ReadAccessor for more info.
+func ReadPrimitive(doc *gltf.Document, prim *gltf.Primitive) (*PrimitiveData, error) {
+	data := new(PrimitiveData)
+	var vertices int
+	for name, index := range prim.Attributes {
+		acr, err := primitiveAccessor(doc, name, index)
+		if err != nil {
+			return nil, err
+		}
+		vertices = acr.Count
+		switch name {
+		case gltf.POSITION:
+			data.Position, err = ReadPosition(doc, acr, nil)
+		case gltf.NORMAL:
+			data.Normal, err = ReadNormal(doc, acr, nil)
+		case gltf.TANGENT:
+			data.Tangent, err = ReadTangent(doc, acr, nil)
+		default:
+			err = readAttributeSet(doc, data, name, acr, prim.Attributes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if data.Position != nil {
+		vertices = len(data.Position)
+	}
+	if prim.Indices != nil {
+		acr, err := primitiveAccessor(doc, "indices", *prim.Indices)
+		if err != nil {
+			return nil, err
+		}
+		if data.Indices, err = ReadIndices(doc, acr, nil); err != nil {
+			return nil, fmt.Errorf("indices: %w", err)
+		}
+	} else {
+		data.Indices = make([]uint32, vertices)
+		for i := range data.Indices {
+			data.Indices[i] = uint32(i)
+		}
+	}
+	for i, target := range prim.Targets {
+		var t MorphTarget
+		for name, index := range target {
+			acr, err := primitiveAccessor(doc, name, index)
+			if err != nil {
+				return nil, fmt.Errorf("target %d: %w", i, err)
+			}
+			switch name {
+			case gltf.POSITION:
+				t.Position, err = ReadPosition(doc, acr, nil)
+			case gltf.NORMAL:
+				t.Normal, err = ReadNormal(doc, acr, nil)
+			case gltf.TANGENT:
+				// Tangent displacements are VEC3, as normals.
+				t.Tangent, err = ReadNormal(doc, acr, nil)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("target %d: %s: %w", i, name, err)
+			}
+		}
+		data.Targets = append(data.Targets, t)
+	}
+	return data, nil
+}
+
+func primitiveAccessor(doc *gltf.Document, name string, index int) (*gltf.Accessor, error) {
+	if index < 0 || index >= len(doc.Accessors) {
+		return nil, fmt.Errorf("%s: gltf: accessor index %d overflows", name, index)
+	}
+	return doc.Accessors[index], nil
+}
+
+// readAttributeSet reads the TEXCOORD_n, COLOR_n, JOINTS_n and WEIGHTS_n attributes into data.
+// As sets are contiguous, the sets 0 to n-1 of the semantic must be in attributes.
+// Other attributes are ignored.
+func readAttributeSet(doc *gltf.Document, data *PrimitiveData, name string, acr *gltf.Accessor, attributes gltf.PrimitiveAttributes) error {
+	semantic, set, ok := strings.Cut(name, "_")
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(set)
+	if err != nil || n < 0 {
+		return nil
+	}
+	switch semantic {
+	case "TEXCOORD", "COLOR", "JOINTS", "WEIGHTS":
+		for i := 0; i < n; i++ {
+			if _, ok := attributes[semantic+"_"+strconv.Itoa(i)]; !ok {
+				return fmt.Errorf("gltf: attribute set %d is not contiguous, set %d is missing", n, i)
+			}
+		}
+	}
+	switch semantic {
+	case "TEXCOORD":
+		data.TextureCoords = growSets(data.TextureCoords, n)
+		data.TextureCoords[n], err = ReadTextureCoord(doc, acr, nil)
+	case "COLOR":
+		data.Colors = growSets(data.Colors, n)
+		data.Colors[n], err = ReadColor(doc, acr, nil)
+	case "JOINTS":
+		data.Joints = growSets(data.Joints, n)
+		data.Joints[n], err = ReadJoints(doc, acr, nil)
+	case "WEIGHTS":
+		data.Weights = growSets(data.Weights, n)
+		data.Weights[n], err = ReadWeights(doc, acr, nil)
+	}
+	return err
+}
+
+// growSets returns sets with room for set n.
+func growSets[T any](sets []T, n int) []T {
+	if n < len(sets) {
+		return sets
+	}
+	return append(sets, make([]T, n+1-len(sets))...)
+}
+
+// ReadAs returns the data referenced by acr converted to elements of type T.
+//
+// T must have the same shape as acr.Type: a number for scalars,
+// an array of numbers for vectors and an array of arrays of numbers
+// for matrices, such as float32, [3]float64 or [4][4]float32.
+// Normalized components are denormalized when T components are floats.
+// Integer components can only be read as integers that can represent
+// all the values of acr.ComponentType.
+//
+// See ReadAccessor for more info.
+func ReadAs[T any](doc *gltf.Document, acr *gltf.Accessor, buffer []T) ([]T, error) {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+	kind, ok := elementKind(tp, acr.Type)
+	if !ok {
+		return nil, fmt.Errorf("gltf: cannot read %s accessor as %s", acr.Type, tp)
+	}
+	if !canConvertComponent(acr.ComponentType, kind) {
+		return nil, fmt.Errorf("gltf: cannot read %s components as %s", acr.ComponentType, kind)
+	}
+	bufPtr := bufPool.Get().(*[]byte)
+	defer bufPool.Put(bufPtr)
+	data, err := ReadAccessor(doc, acr, *bufPtr)
+	if err != nil {
+		return nil, err
+	}
+	buffer = makeBufferOf(acr.Count, buffer)
+	denormalize := acr.Normalized && (kind == reflect.Float32 || kind == reflect.Float64)
+	if data, ok := data.([]T); ok && !denormalize {
+		copy(buffer, data)
+		return buffer, nil
+	}
+	src, dst := reflect.ValueOf(data), reflect.ValueOf(buffer)
+	components := make([]float64, 0, acr.Type.Components())
+	for i := 0; i < src.Len(); i++ {
+		components = appendComponents(components[:0], src.Index(i))
+		if denormalize {
+			for j, x := range components {
+				components[j] = denormalizeComponent(acr.ComponentType, x)
+			}
+		}
+		setComponents(dst.Index(i), components)
+	}
+	return buffer, nil
+}
+
+// elementKind returns the kind of the components of tp
+// and whether tp has the shape of an element of type t.
+func elementKind(tp reflect.Type, t gltf.AccessorType) (reflect.Kind, bool) {
+	switch t {
+	case gltf.AccessorVec2, gltf.AccessorVec3, gltf.AccessorVec4:
+		if tp.Kind() != reflect.Array || tp.Len() != t.Components() {
+			return reflect.Invalid, false
+		}
+		tp = tp.Elem()
+	case gltf.AccessorMat2, gltf.AccessorMat3, gltf.AccessorMat4:
+		n := int(math.Sqrt(float64(t.Components())))
+		if tp.Kind() != reflect.Array || tp.Len() != n || tp.Elem().Kind() != reflect.Array || tp.Elem().Len() != n {
+			return reflect.Invalid, false
+		}
+		tp = tp.Elem().Elem()
+	}
+	switch kind := tp.Kind(); kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kind, true
+	}
+	return reflect.Invalid, false
+}
+
+// canConvertComponent reports whether all the values of c can be represented by kind.
+func canConvertComponent(c gltf.ComponentType, kind reflect.Kind) bool {
+	if kind == reflect.Float32 || kind == reflect.Float64 {
+		return true
+	}
+	var signed, unsigned int // Minimum bits required to hold the values.
+	switch c {
+	case gltf.ComponentByte:
+		signed, unsigned = 8, -1
+	case gltf.ComponentUbyte:
+		signed, unsigned = 16, 8
+	case gltf.ComponentShort:
+		signed, unsigned = 16, -1
+	case gltf.ComponentUshort:
+		signed, unsigned = 32, 16
+	case gltf.ComponentUint:
+		signed, unsigned = 64, 32
+	default:
+		return false
+	}
+	switch kind {
+	case reflect.Int8:
+		return signed <= 8
+	case reflect.Int16:
+		return signed <= 16
+	case reflect.Int32:
+		return signed <= 32
+	case reflect.Int, reflect.Int64:
+		return true
+	case reflect.Uint8:
+		return unsigned != -1 && unsigned <= 8
+	case reflect.Uint16:
+		return unsigned != -1 && unsigned <= 16
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return unsigned != -1
+	}
+	return false
+}
+
+func denormalizeComponent(c gltf.ComponentType, x float64) float64 {
+	switch c {
+	case gltf.ComponentByte:
+		return math.Max(x/math.MaxInt8, -1)
+	case gltf.ComponentUbyte:
+		return x / math.MaxUint8
+	case gltf.ComponentShort:
+		return math.Max(x/math.MaxInt16, -1)
+	case gltf.ComponentUshort:
+		return x / math.MaxUint16
+	case gltf.ComponentUint:
+		return x / math.MaxUint32
+	}
+	return x
+}
+
+// setComponents sets the components of the scalar, vector or matrix v from src,
+// which is in column-major order, and returns the remaining components.
+func setComponents(v reflect.Value, src []float64) []float64 {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			src = setComponents(v.Index(i), src)
+		}
+		return src
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(src[0]))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(src[0]))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(src[0])
+	}
+	return src[1:]
+}
+
 func errAccessorType(tp gltf.AccessorType) error {
 	return fmt.Errorf("gltf: accessor type %v not allowed", tp)
 }
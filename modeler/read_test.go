@@ -101,6 +101,17 @@ func TestReadAccessor_Buffered(t *testing.T) {
 	}
 }
 
+func TestReadAccessor_BufferedWithoutBufferView(t *testing.T) {
+	acr := &gltf.Accessor{ComponentType: gltf.ComponentUbyte, Type: gltf.AccessorScalar, Count: 3}
+	data, err := modeler.ReadAccessor(new(gltf.Document), acr, []byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0, 0, 0}; !reflect.DeepEqual(data, want) {
+		t.Errorf("ReadAccessor = %v, want %v", data, want)
+	}
+}
+
 func TestReadAccessor(t *testing.T) {
 	type args struct {
 		doc *gltf.Document
@@ -305,7 +316,7 @@ func TestReadNormal(t *testing.T) {
 			BufferView: gltf.Index(0), Type: gltf.AccessorMat2, ComponentType: gltf.ComponentFloat,
 		}, nil}, nil, true},
 		{"incorrect-componenttype", args{[]byte{}, &gltf.Accessor{
-			BufferView: gltf.Index(0), Type: gltf.AccessorVec3, ComponentType: gltf.ComponentByte,
+			BufferView: gltf.Index(0), Type: gltf.AccessorVec3, ComponentType: gltf.ComponentUint,
 		}, nil}, nil, true},
 	}
 	for _, tt := range tests {
@@ -352,7 +363,7 @@ func TestReadTangent(t *testing.T) {
 			BufferView: gltf.Index(0), Type: gltf.AccessorMat2, ComponentType: gltf.ComponentFloat,
 		}, nil}, nil, true},
 		{"incorrect-componenttype", args{[]byte{}, &gltf.Accessor{
-			BufferView: gltf.Index(0), Type: gltf.AccessorVec4, ComponentType: gltf.ComponentByte,
+			BufferView: gltf.Index(0), Type: gltf.AccessorVec4, ComponentType: gltf.ComponentUint,
 		}, nil}, nil, true},
 	}
 	for _, tt := range tests {
@@ -405,7 +416,7 @@ func TestReadTextureCoord(t *testing.T) {
 			BufferView: gltf.Index(0), Type: gltf.AccessorMat2, ComponentType: gltf.ComponentFloat,
 		}, nil}, nil, true},
 		{"incorrect-componenttype", args{[]byte{}, &gltf.Accessor{
-			BufferView: gltf.Index(0), Type: gltf.AccessorVec2, ComponentType: gltf.ComponentByte,
+			BufferView: gltf.Index(0), Type: gltf.AccessorVec2, ComponentType: gltf.ComponentUint,
 		}, nil}, nil, true},
 	}
 	for _, tt := range tests {
@@ -555,7 +566,7 @@ func TestReadPosition(t *testing.T) {
 			BufferView: gltf.Index(0), Type: gltf.AccessorMat2, ComponentType: gltf.ComponentFloat,
 		}, nil}, nil, true},
 		{"incorrect-componenttype", args{[]byte{}, &gltf.Accessor{
-			BufferView: gltf.Index(0), Type: gltf.AccessorVec3, ComponentType: gltf.ComponentByte,
+			BufferView: gltf.Index(0), Type: gltf.AccessorVec3, ComponentType: gltf.ComponentUint,
 		}, nil}, nil, true},
 	}
 	for _, tt := range tests {
@@ -748,3 +759,147 @@ func TestReadInverseBindMatrices(t *testing.T) {
 	}
 
 }
+
+func TestReadPrimitive(t *testing.T) {
+	doc := gltf.NewDocument()
+	attrs, err := modeler.WritePrimitiveAttributes(doc,
+		modeler.PrimitiveAttribute{Name: gltf.POSITION, Data: [][3]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}},
+		modeler.PrimitiveAttribute{Name: gltf.NORMAL, Data: [][3]float32{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}}},
+		modeler.PrimitiveAttribute{Name: "TEXCOORD_1", Data: [][2]float32{{0, 1}, {1, 0}, {1, 1}}},
+		modeler.PrimitiveAttribute{Name: "TEXCOORD_0", Data: [][2]uint8{{0, 255}, {255, 0}, {255, 255}}},
+		modeler.PrimitiveAttribute{Name: gltf.JOINTS_0, Data: [][4]uint8{{1, 2, 3, 4}, {1, 2, 3, 4}, {1, 2, 3, 4}}},
+		modeler.PrimitiveAttribute{Name: gltf.WEIGHTS_0, Data: [][4]float32{{1, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}}},
+		modeler.PrimitiveAttribute{Name: gltf.COLOR_0, Data: [][4]uint8{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}}},
+		modeler.PrimitiveAttribute{Name: "_CUSTOM", Data: []float32{1, 2, 3}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prim := &gltf.Primitive{Attributes: attrs}
+	mesh := &gltf.Mesh{Primitives: []*gltf.Primitive{prim}}
+	err = modeler.WriteMorphTargets(doc, mesh, prim, modeler.MorphTarget{Position: [][3]float32{{}, {1, 1, 1}, {}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &modeler.PrimitiveData{
+		Indices:       []uint32{0, 1, 2},
+		Position:      [][3]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}},
+		Normal:        [][3]float32{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}},
+		TextureCoords: [][][2]float32{{{0, 1}, {1, 0}, {1, 1}}, {{0, 1}, {1, 0}, {1, 1}}},
+		Colors:        [][][4]uint8{{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}}},
+		Joints:        [][][4]uint16{{{1, 2, 3, 4}, {1, 2, 3, 4}, {1, 2, 3, 4}}},
+		Weights:       [][][4]float32{{{1, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}}},
+		Targets:       []modeler.MorphTarget{{Position: [][3]float32{{}, {1, 1, 1}, {}}}},
+	}
+	got, err := modeler.ReadPrimitive(doc, prim)
+	if err != nil {
+		t.Fatalf("ReadPrimitive() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadPrimitive() = %v, want %v", got, want)
+	}
+
+	prim.Indices = gltf.Index(modeler.WriteIndices(doc, []uint16{2, 1, 0}))
+	got, err = modeler.ReadPrimitive(doc, prim)
+	if err != nil {
+		t.Fatalf("ReadPrimitive() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Indices, []uint32{2, 1, 0}) {
+		t.Errorf("ReadPrimitive() indices = %v, want [2 1 0]", got.Indices)
+	}
+}
+
+func TestReadPrimitive_Error(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{1, 2, 3}})
+	uv := modeler.WriteTextureCoord(doc, [][2]float32{{0, 1}})
+	tests := []struct {
+		name string
+		prim *gltf.Primitive
+	}{
+		{"overflow", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: 5}}},
+		{"type", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.TANGENT: pos}}},
+		{"indices", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos}, Indices: gltf.Index(pos)}},
+		{"set", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos, "TEXCOORD_9": pos}}},
+		{"setHole", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos, gltf.TEXCOORD_1: uv}}},
+		{"target", &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos}, Targets: []gltf.PrimitiveAttributes{{gltf.POSITION: 7}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := modeler.ReadPrimitive(doc, tt.prim); err == nil {
+				t.Error("ReadPrimitive() expected error")
+			}
+		})
+	}
+}
+
+func TestReadAs(t *testing.T) {
+	doc := gltf.NewDocument()
+	vec3 := modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, [][3]float32{{1, 2, 3}, {4, 5, 6}})
+	uv := modeler.WriteTextureCoord(doc, [][2]uint8{{255, 0}, {51, 102}})
+	scalar := modeler.WriteAccessor(doc, gltf.TargetNone, []int16{-3, 7})
+	mat := modeler.WriteAccessor(doc, gltf.TargetNone, [][2][2]uint8{{{1, 2}, {3, 4}}})
+	sparse, _ := modeler.WriteSparseAccessor(doc, nil, []uint16{0, 0, 0, 0, 0, 0, 9})
+	t.Run("same", func(t *testing.T) {
+		got, err := modeler.ReadAs[[3]float32](doc, doc.Accessors[vec3], nil)
+		if err != nil || !reflect.DeepEqual(got, [][3]float32{{1, 2, 3}, {4, 5, 6}}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("float64", func(t *testing.T) {
+		got, err := modeler.ReadAs[[3]float64](doc, doc.Accessors[vec3], nil)
+		if err != nil || !reflect.DeepEqual(got, [][3]float64{{1, 2, 3}, {4, 5, 6}}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("normalized", func(t *testing.T) {
+		got, err := modeler.ReadAs[[2]float64](doc, doc.Accessors[uv], nil)
+		if err != nil || !reflect.DeepEqual(got, [][2]float64{{1, 0}, {0.2, 0.4}}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("normalizedAsInt", func(t *testing.T) {
+		got, err := modeler.ReadAs[[2]uint16](doc, doc.Accessors[uv], make([][2]uint16, 5))
+		if err != nil || !reflect.DeepEqual(got, [][2]uint16{{255, 0}, {51, 102}}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("scalar", func(t *testing.T) {
+		got, err := modeler.ReadAs[int](doc, doc.Accessors[scalar], nil)
+		if err != nil || !reflect.DeepEqual(got, []int{-3, 7}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("matrix", func(t *testing.T) {
+		got, err := modeler.ReadAs[[2][2]float32](doc, doc.Accessors[mat], nil)
+		if err != nil || !reflect.DeepEqual(got, [][2][2]float32{{{1, 2}, {3, 4}}}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	t.Run("sparse", func(t *testing.T) {
+		got, err := modeler.ReadAs[uint32](doc, doc.Accessors[sparse], nil)
+		if err != nil || !reflect.DeepEqual(got, []uint32{0, 0, 0, 0, 0, 0, 9}) {
+			t.Errorf("ReadAs() = %v, %v", got, err)
+		}
+	})
+	errTests := []struct {
+		name string
+		read func() error
+	}{
+		{"vecLength", func() error { _, err := modeler.ReadAs[[2]float32](doc, doc.Accessors[vec3], nil); return err }},
+		{"vecAsScalar", func() error { _, err := modeler.ReadAs[float32](doc, doc.Accessors[vec3], nil); return err }},
+		{"scalarAsVec", func() error { _, err := modeler.ReadAs[[1]float32](doc, doc.Accessors[scalar], nil); return err }},
+		{"matAsVec", func() error { _, err := modeler.ReadAs[[4]uint8](doc, doc.Accessors[mat], nil); return err }},
+		{"floatAsInt", func() error { _, err := modeler.ReadAs[[3]int32](doc, doc.Accessors[vec3], nil); return err }},
+		{"signedAsUnsigned", func() error { _, err := modeler.ReadAs[uint32](doc, doc.Accessors[scalar], nil); return err }},
+		{"narrow", func() error { _, err := modeler.ReadAs[int8](doc, doc.Accessors[scalar], nil); return err }},
+		{"nonNumeric", func() error { _, err := modeler.ReadAs[string](doc, doc.Accessors[scalar], nil); return err }},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.read(); err == nil {
+				t.Error("ReadAs() expected error")
+			}
+		})
+	}
+}
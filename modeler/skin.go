@@ -0,0 +1,218 @@
+package modeler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qmuntal/gltf"
+)
+
+// WriteSkin is like Writer.WriteSkin but writes into the last buffer of doc.
+func WriteSkin(doc *gltf.Document, node int, joints []int, bindPose [][4][4]float32) (int, error) {
+	return lastWriter(doc).WriteSkin(node, joints, bindPose)
+}
+
+// WriteSkin adds a new skin to the document made of joints and binds it to node,
+// which must instantiate a mesh. If success it returns the index of the new skin.
+//
+// bindPose contains the world transform of each joint, as column-major matrices,
+// when the mesh is bound to the skeleton. If it is nil, the current world transforms
+// of the joints are used. Its inverses are written as the inverse bind matrices.
+//
+// The skeleton root is set to the joint that is an ancestor of all the others, if any.
+// An error is returned if a JOINTS_n attribute of the mesh references a joint out of range.
+func (w *Writer) WriteSkin(node int, joints []int, bindPose [][4][4]float32) (int, error) {
+	if node < 0 || node >= len(w.doc.Nodes) {
+		return 0, fmt.Errorf("gltf: node index %d overflows", node)
+	}
+	if len(joints) == 0 {
+		return 0, errors.New("gltf: skin without joints")
+	}
+	if bindPose != nil && len(bindPose) != len(joints) {
+		return 0, fmt.Errorf("gltf: bind pose has %d matrices but there are %d joints", len(bindPose), len(joints))
+	}
+	seen := make(map[int]bool, len(joints))
+	for _, j := range joints {
+		if j < 0 || j >= len(w.doc.Nodes) {
+			return 0, fmt.Errorf("gltf: joint node index %d overflows", j)
+		}
+		if seen[j] {
+			return 0, fmt.Errorf("gltf: duplicated joint node %d", j)
+		}
+		seen[j] = true
+	}
+	if err := checkSkinnedMesh(w.doc, w.doc.Nodes[node].Mesh, len(joints)); err != nil {
+		return 0, err
+	}
+
+	parents := nodeParents(w.doc)
+	ibm := make([][4][4]float32, len(joints))
+	for i, j := range joints {
+		var m [16]float64
+		if bindPose != nil {
+			for c := range bindPose[i] {
+				for r, x := range bindPose[i][c] {
+					m[c*4+r] = float64(x)
+				}
+			}
+		} else {
+			m = worldMatrix(w.doc, parents, j)
+		}
+		inv, ok := invertMatrix(m)
+		if !ok {
+			return 0, fmt.Errorf("gltf: bind pose of joint %d is not invertible", j)
+		}
+		for c := range ibm[i] {
+			for r := range ibm[i][c] {
+				ibm[i][c][r] = float32(inv[c*4+r])
+			}
+		}
+	}
+
+	skin := &gltf.Skin{
+		InverseBindMatrices: gltf.Index(w.WriteInverseBindMatrices(ibm)),
+		Joints:              append([]int(nil), joints...),
+	}
+	for _, j := range joints {
+		if isSkeletonRoot(parents, j, joints) {
+			skin.Skeleton = gltf.Index(j)
+			break
+		}
+	}
+	w.doc.Skins = append(w.doc.Skins, skin)
+	index := len(w.doc.Skins) - 1
+	w.doc.Nodes[node].Skin = gltf.Index(index)
+	return index, nil
+}
+
+// checkSkinnedMesh checks that the JOINTS_n attributes of mesh reference less than count joints.
+func checkSkinnedMesh(doc *gltf.Document, mesh *int, count int) error {
+	if mesh == nil || *mesh < 0 || *mesh >= len(doc.Meshes) {
+		return errors.New("gltf: skinned node does not instantiate a mesh")
+	}
+	for _, p := range doc.Meshes[*mesh].Primitives {
+		for name, index := range p.Attributes {
+			if !strings.HasPrefix(name, "JOINTS_") {
+				continue
+			}
+			if index < 0 || index >= len(doc.Accessors) {
+				return fmt.Errorf("gltf: %s accessor index %d overflows", name, index)
+			}
+			data, err := ReadJoints(doc, doc.Accessors[index], nil)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			for _, v := range data {
+				for _, j := range v {
+					if int(j) >= count {
+						return fmt.Errorf("gltf: %s references joint %d but the skin has %d joints", name, j, count)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// nodeParents returns the parent of each child node.
+func nodeParents(doc *gltf.Document) map[int]int {
+	parents := make(map[int]int)
+	for i, n := range doc.Nodes {
+		for _, c := range n.Children {
+			parents[c] = i
+		}
+	}
+	return parents
+}
+
+// isSkeletonRoot reports whether root is an ancestor of all the other joints.
+func isSkeletonRoot(parents map[int]int, root int, joints []int) bool {
+	for _, j := range joints {
+		n := j
+		for i := 0; n != root && i <= len(parents); i++ {
+			p, ok := parents[n]
+			if !ok {
+				break
+			}
+			n = p
+		}
+		if n != root {
+			return false
+		}
+	}
+	return true
+}
+
+// worldMatrix returns the column-major world transform of node.
+func worldMatrix(doc *gltf.Document, parents map[int]int, node int) [16]float64 {
+	m := localMatrix(doc.Nodes[node])
+	// Parent cycles are cut after visiting as many nodes as the document has.
+	for i := 0; i < len(doc.Nodes); i++ {
+		parent, ok := parents[node]
+		if !ok {
+			break
+		}
+		m = mulMatrix(localMatrix(doc.Nodes[parent]), m)
+		node = parent
+	}
+	return m
+}
+
+// localMatrix returns the column-major local transform of n,
+// either its matrix or the composition of its translation, rotation and scale.
+func localMatrix(n *gltf.Node) [16]float64 {
+	if m := n.MatrixOrDefault(); m != gltf.DefaultMatrix {
+		return m
+	}
+	q, s, t := n.RotationOrDefault(), n.ScaleOrDefault(), n.TranslationOrDefault()
+	x, y, z, w := q[0], q[1], q[2], q[3]
+	return [16]float64{
+		(1 - 2*(y*y+z*z)) * s[0], 2 * (x*y + z*w) * s[0], 2 * (x*z - y*w) * s[0], 0,
+		2 * (x*y - z*w) * s[1], (1 - 2*(x*x+z*z)) * s[1], 2 * (y*z + x*w) * s[1], 0,
+		2 * (x*z + y*w) * s[2], 2 * (y*z - x*w) * s[2], (1 - 2*(x*x+y*y)) * s[2], 0,
+		t[0], t[1], t[2], 1,
+	}
+}
+
+// mulMatrix returns a*b, both being column-major matrices.
+func mulMatrix(a, b [16]float64) [16]float64 {
+	var m [16]float64
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			for k := 0; k < 4; k++ {
+				m[c*4+r] += a[k*4+r] * b[c*4+k]
+			}
+		}
+	}
+	return m
+}
+
+// invertMatrix returns the inverse of m and whether m is invertible.
+func invertMatrix(m [16]float64) ([16]float64, bool) {
+	var inv [16]float64
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	det := m[0]*inv[0] + m[1]*inv[4] + m[2]*inv[8] + m[3]*inv[12]
+	if det == 0 {
+		return inv, false
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
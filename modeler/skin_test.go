@@ -0,0 +1,104 @@
+package modeler_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func newSkinnedDoc(joints [][4]uint8) *gltf.Document {
+	doc := gltf.NewDocument()
+	doc.Nodes = []*gltf.Node{
+		{Mesh: gltf.Index(0)},
+		{Children: []int{2}, Translation: [3]float64{0, 1, 0}},
+		{Translation: [3]float64{0, 2, 0}, Rotation: [4]float64{0, 0, 0.7071068, 0.7071068}},
+	}
+	doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{{
+		Attributes: gltf.PrimitiveAttributes{gltf.JOINTS_0: modeler.WriteJoints(doc, joints)},
+	}}}}
+	return doc
+}
+
+func TestWriteSkin(t *testing.T) {
+	doc := newSkinnedDoc([][4]uint8{{0, 1, 0, 0}})
+	joints := []int{2, 1}
+	index, err := modeler.WriteSkin(doc, 0, joints, nil)
+	if err != nil {
+		t.Fatalf("WriteSkin() error = %v", err)
+	}
+	joints[0] = 0 // The skin must not share the caller slice.
+	skin := doc.Skins[index]
+	if diff := deep.Equal(skin.Joints, []int{2, 1}); diff != nil {
+		t.Errorf("WriteSkin() joints = %v", diff)
+	}
+	if diff := deep.Equal(skin.Skeleton, gltf.Index(1)); diff != nil {
+		t.Errorf("WriteSkin() skeleton = %v", diff)
+	}
+	if diff := deep.Equal(doc.Nodes[0].Skin, gltf.Index(index)); diff != nil {
+		t.Errorf("WriteSkin() node skin = %v", diff)
+	}
+	got, err := modeler.ReadInverseBindMatrices(doc, doc.Accessors[*skin.InverseBindMatrices], nil)
+	if err != nil {
+		t.Fatalf("ReadInverseBindMatrices() error = %v", err)
+	}
+	want := [][4][4]float32{
+		{{0, -1, 0, 0}, {1, 0, 0, 0}, {0, 0, 1, 0}, {-3, 0, 0, 1}},
+		{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, -1, 0, 1}},
+	}
+	for i := range got {
+		for c := range got[i] {
+			for r, x := range got[i][c] {
+				// Round the precision errors and turn -0 into 0.
+				got[i][c][r] = float32(math.Round(float64(x)*1e5)/1e5) + 0
+			}
+		}
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("WriteSkin() inverse bind matrices = %v", diff)
+	}
+}
+
+func TestWriteSkin_BindPose(t *testing.T) {
+	doc := newSkinnedDoc([][4]uint8{{0, 0, 0, 0}})
+	index, err := modeler.WriteSkin(doc, 0, []int{1}, [][4][4]float32{{{2, 0, 0, 0}, {0, 2, 0, 0}, {0, 0, 2, 0}, {4, 0, 0, 1}}})
+	if err != nil {
+		t.Fatalf("WriteSkin() error = %v", err)
+	}
+	got, _ := modeler.ReadInverseBindMatrices(doc, doc.Accessors[*doc.Skins[index].InverseBindMatrices], nil)
+	want := [][4][4]float32{{{0.5, 0, 0, 0}, {0, 0.5, 0, 0}, {0, 0, 0.5, 0}, {-2, 0, 0, 1}}}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("WriteSkin() inverse bind matrices = %v", diff)
+	}
+}
+
+func TestWriteSkin_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     int
+		joints   []int
+		bindPose [][4][4]float32
+	}{
+		{"node", 3, []int{1, 2}, nil},
+		{"noMesh", 1, []int{1, 2}, nil},
+		{"noJoints", 0, nil, nil},
+		{"jointOverflow", 0, []int{1, 3}, nil},
+		{"duplicated", 0, []int{1, 1}, nil},
+		{"bindPoseLength", 0, []int{1, 2}, [][4][4]float32{{}}},
+		{"singular", 0, []int{1, 2}, [][4][4]float32{{}, {}}},
+		{"jointsRange", 0, []int{1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := newSkinnedDoc([][4]uint8{{0, 1, 0, 0}})
+			if _, err := modeler.WriteSkin(doc, tt.node, tt.joints, tt.bindPose); err == nil {
+				t.Error("WriteSkin() expected error")
+			}
+			if len(doc.Skins) != 0 {
+				t.Error("WriteSkin() added a skin on error")
+			}
+		})
+	}
+}
@@ -3,8 +3,6 @@
 package modeler
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
 	"image/color"
 	"io"
@@ -12,46 +10,34 @@ import (
 	"reflect"
 
 	"github.com/qmuntal/gltf"
-	"github.com/qmuntal/gltf/binary"
 )
 
 // WriteIndices adds a new INDICES accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteIndices(doc *gltf.Document, data any) int {
-	switch data.(type) {
-	case []uint16, []uint32:
-	default:
-		panic(fmt.Sprintf("modeler.WriteIndices: invalid type %T", data))
-	}
-	return WriteAccessor(doc, gltf.TargetElementArrayBuffer, data)
+func WriteIndices(doc *gltf.Document, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteIndices(data, opts...)
 }
 
 // WriteNormal adds a new NORMAL accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteNormal(doc *gltf.Document, data [][3]float32) int {
-	return WriteAccessor(doc, gltf.TargetArrayBuffer, data)
+func WriteNormal(doc *gltf.Document, data [][3]float32, opts ...WriteOption) int {
+	return lastWriter(doc).WriteNormal(data, opts...)
 }
 
 // WriteTangent adds a new TANGENT accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteTangent(doc *gltf.Document, data [][4]float32) int {
-	return WriteAccessor(doc, gltf.TargetArrayBuffer, data)
+func WriteTangent(doc *gltf.Document, data [][4]float32, opts ...WriteOption) int {
+	return lastWriter(doc).WriteTangent(data, opts...)
 }
 
 // WriteTextureCoord adds a new TEXTURECOORD accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteTextureCoord(doc *gltf.Document, data any) int {
-	normalized, err := checkTextureCoord(data)
-	if err != nil {
-		panic(err)
-	}
-	index := WriteAccessor(doc, gltf.TargetArrayBuffer, data)
-	doc.Accessors[index].Normalized = normalized
-	return index
+func WriteTextureCoord(doc *gltf.Document, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteTextureCoord(data, opts...)
 }
 
 func checkTextureCoord(data any) (bool, error) {
@@ -69,14 +55,8 @@ func checkTextureCoord(data any) (bool, error) {
 // WriteWeights adds a new WEIGHTS accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteWeights(doc *gltf.Document, data any) int {
-	normalized, err := checkWeights(data)
-	if err != nil {
-		panic(err)
-	}
-	index := WriteAccessor(doc, gltf.TargetArrayBuffer, data)
-	doc.Accessors[index].Normalized = normalized
-	return index
+func WriteWeights(doc *gltf.Document, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteWeights(data, opts...)
 }
 
 func checkWeights(data any) (bool, error) {
@@ -94,19 +74,15 @@ func checkWeights(data any) (bool, error) {
 // WriteJoints adds a new JOINTS accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteJoints(doc *gltf.Document, data any) int {
-	err := checkJoints(data)
-	if err != nil {
-		panic(err)
-	}
-	return WriteAccessor(doc, gltf.TargetArrayBuffer, data)
+func WriteJoints(doc *gltf.Document, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteJoints(data, opts...)
 }
 
 // WriteInverseBindMatrices adds a new inverse bind matrices accessor to doc
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
-func WriteInverseBindMatrices(doc *gltf.Document, data [][4][4]float32) int {
-	return WriteAccessor(doc, gltf.TargetArrayBuffer, data)
+func WriteInverseBindMatrices(doc *gltf.Document, data [][4][4]float32, opts ...WriteOption) int {
+	return lastWriter(doc).WriteInverseBindMatrices(data, opts...)
 }
 
 func checkJoints(data any) error {
@@ -122,11 +98,7 @@ func checkJoints(data any) error {
 // and fills the last buffer with data.
 // If success it returns the index of the new accessor.
 func WritePosition(doc *gltf.Document, data [][3]float32) int {
-	index := WriteAccessor(doc, gltf.TargetArrayBuffer, data)
-	min, max := minMaxFloat32(data)
-	doc.Accessors[index].Min = min[:]
-	doc.Accessors[index].Max = max[:]
-	return index
+	return lastWriter(doc).WritePosition(data)
 }
 
 func minMaxFloat32(data [][3]float32) ([3]float64, [3]float64) {
@@ -144,14 +116,8 @@ func minMaxFloat32(data [][3]float32) ([3]float64, [3]float64) {
 // WriteColor adds a new COLOR accessor to doc
 // and fills the buffer with data.
 // If success it returns the index of the new accessor.
-func WriteColor(doc *gltf.Document, data any) int {
-	normalized, err := checkColor(data)
-	if err != nil {
-		panic(err)
-	}
-	index := WriteAccessor(doc, gltf.TargetArrayBuffer, data)
-	doc.Accessors[index].Normalized = normalized
-	return index
+func WriteColor(doc *gltf.Document, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteColor(data, opts...)
 }
 
 func checkColor(data any) (bool, error) {
@@ -170,41 +136,88 @@ func checkColor(data any) (bool, error) {
 // and fills the buffer with the image data.
 // If success it returns the index of the new image.
 func WriteImage(doc *gltf.Document, name string, mimeType string, r io.Reader) (int, error) {
-	var data []byte
-	switch r := r.(type) {
-	case *bytes.Buffer:
-		data = r.Bytes()
-	default:
-		var err error
-		data, err = io.ReadAll(r)
-		if err != nil {
-			return 0, err
-		}
-	}
-	index := WriteBufferView(doc, gltf.TargetNone, data)
-	doc.Images = append(doc.Images, &gltf.Image{
-		Name:       name,
-		MimeType:   mimeType,
-		BufferView: gltf.Index(index),
-	})
-	return len(doc.Images) - 1, nil
+	return lastWriter(doc).WriteImage(name, mimeType, r)
 }
 
 // WriteAccessor adds a new Accessor to doc
 // and fills the buffer with the data.
 // Returns the index of the new accessor.
-func WriteAccessor(doc *gltf.Document, target gltf.Target, data any) int {
-	ensurePadding(doc)
-	index := WriteBufferView(doc, target, data)
-	c, a, l := binary.Type(data)
-	doc.Accessors = append(doc.Accessors, &gltf.Accessor{
-		BufferView:    gltf.Index(index),
-		ByteOffset:    0,
-		ComponentType: c,
-		Type:          a,
-		Count:         l,
-	})
-	return len(doc.Accessors) - 1
+//
+// The other single accessor writers also accept opts,
+// which are applied once the accessor is written.
+func WriteAccessor(doc *gltf.Document, target gltf.Target, data any, opts ...WriteOption) int {
+	return lastWriter(doc).WriteAccessor(target, data, opts...)
+}
+
+// WriteSparseAccessor is like Writer.WriteSparseAccessor but writes into the last buffer of doc.
+func WriteSparseAccessor(doc *gltf.Document, base *int, data any, opts ...WriteOption) (int, error) {
+	return lastWriter(doc).WriteSparseAccessor(base, data, opts...)
+}
+
+// sparseIndices converts indices to the smallest unsigned integer slice that can hold them.
+func sparseIndices(indices []int) (any, gltf.ComponentType) {
+	switch max := indices[len(indices)-1]; {
+	case max <= math.MaxUint8:
+		data := make([]uint8, len(indices))
+		for i, x := range indices {
+			data[i] = uint8(x)
+		}
+		return data, gltf.ComponentUbyte
+	case max <= math.MaxUint16:
+		data := make([]uint16, len(indices))
+		for i, x := range indices {
+			data[i] = uint16(x)
+		}
+		return data, gltf.ComponentUshort
+	}
+	data := make([]uint32, len(indices))
+	for i, x := range indices {
+		data[i] = uint32(x)
+	}
+	return data, gltf.ComponentUint
+}
+
+// MorphTarget holds the attribute displacements of a morph target.
+// Empty attributes are not written.
+type MorphTarget struct {
+	Name     string
+	Position [][3]float32
+	Normal   [][3]float32
+	Tangent  [][3]float32
+}
+
+// WriteMorphTargets is like Writer.WriteMorphTargets but writes into the last buffer of doc.
+func WriteMorphTargets(doc *gltf.Document, mesh *gltf.Mesh, primitive *gltf.Primitive, targets ...MorphTarget) error {
+	return lastWriter(doc).WriteMorphTargets(mesh, primitive, targets...)
+}
+
+// setTargetNames sets the names of targets, starting at offset,
+// in the count long mesh.Extras["targetNames"] list.
+func setTargetNames(mesh *gltf.Mesh, offset, count int, targets []MorphTarget) error {
+	var extras map[string]any
+	switch e := mesh.Extras.(type) {
+	case nil:
+		extras = make(map[string]any)
+	case map[string]any:
+		extras = e
+	default:
+		return fmt.Errorf("gltf: cannot set targetNames in mesh extras of type %T", mesh.Extras)
+	}
+	names := make([]any, count)
+	if old, ok := extras["targetNames"].([]any); ok {
+		copy(names, old)
+	}
+	for i := range names {
+		if names[i] == nil {
+			names[i] = ""
+		}
+	}
+	for i, t := range targets {
+		names[offset+i] = t.Name
+	}
+	extras["targetNames"] = names
+	mesh.Extras = extras
+	return nil
 }
 
 // WriteAccessorsInterleaved adds as many accessors as
@@ -213,27 +226,10 @@ func WriteAccessor(doc *gltf.Document, target gltf.Target, data any) int {
 // Returns an slice with the indices of the newly created accessors,
 // with the same order as data or an error if the data elements
 // don´t have all the same length.
+//
+// Use a Writer created with WithBounds to compute the bounds of the accessors.
 func WriteAccessorsInterleaved(doc *gltf.Document, data ...any) ([]int, error) {
-	ensurePadding(doc)
-	index, err := WriteBufferViewInterleaved(doc, data...)
-	if err != nil {
-		return nil, err
-	}
-	indices := make([]int, len(data))
-	var byteOffset int
-	for i, d := range data {
-		c, t, l := binary.Type(d)
-		doc.Accessors = append(doc.Accessors, &gltf.Accessor{
-			BufferView:    gltf.Index(index),
-			ByteOffset:    byteOffset,
-			ComponentType: c,
-			Type:          t,
-			Count:         l,
-		})
-		byteOffset += gltf.SizeOfElement(c, t)
-		indices[i] = len(doc.Accessors) - 1
-	}
-	return indices, nil
+	return lastWriter(doc).WriteAccessorsInterleaved(data...)
 }
 
 // PrimitiveAttribute holds the data referenced by a gltf.PrimitiveAttributes entry.
@@ -244,57 +240,11 @@ type PrimitiveAttribute struct {
 
 // WritePrimitiveAttributes write all the primitives attributes to doc as interleaved data.
 // Returns an attribute map that can be directly used as a primitive attributes.
+//
+// Only POSITION gets min and max, use a Writer created with WithBounds
+// to compute the bounds of all the attributes.
 func WritePrimitiveAttributes(doc *gltf.Document, attr ...PrimitiveAttribute) (gltf.PrimitiveAttributes, error) {
-	type attrProps struct {
-		Name       string
-		Normalized bool
-	}
-	data := make([]any, 0, len(attr))
-	props := make([]attrProps, 0, len(attr))
-	var min, max [3]float64
-	var err error
-	for _, a := range attr {
-		if sliceLength(a.Data) == 0 {
-			continue
-		}
-		var normalized bool
-		switch a.Name {
-		case gltf.POSITION:
-			if v, ok := a.Data.([][3]float32); ok {
-				min, max = minMaxFloat32(v)
-			} else {
-				err = fmt.Errorf("invalid type %T", data)
-			}
-		case gltf.TEXCOORD_0, gltf.TEXCOORD_1:
-			normalized, err = checkTextureCoord(a.Data)
-		case gltf.WEIGHTS_0:
-			normalized, err = checkWeights(a.Data)
-		case gltf.JOINTS_0:
-			err = checkJoints(a.Data)
-		case gltf.COLOR_0:
-			normalized, err = checkColor(a.Data)
-		}
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", a.Name, err)
-		}
-		data = append(data, a.Data)
-		props = append(props, attrProps{Name: a.Name, Normalized: normalized})
-	}
-	indices, err := WriteAccessorsInterleaved(doc, data...)
-	if err != nil {
-		return nil, err
-	}
-	attrs := make(gltf.PrimitiveAttributes, len(props))
-	for i, index := range indices {
-		prop := props[i]
-		attrs[prop.Name] = index
-		doc.Accessors[index].Normalized = prop.Normalized
-	}
-	if pos, ok := attrs[gltf.POSITION]; ok {
-		doc.Accessors[pos].Min = min[:]
-		doc.Accessors[pos].Max = max[:]
-	}
-	return attrs, nil
+	return lastWriter(doc).WritePrimitiveAttributes(attr...)
 }
 
 // WriteBufferViewInterleaved adds a new BufferView to doc
@@ -303,68 +253,23 @@ func WritePrimitiveAttributes(doc *gltf.Document, attr ...PrimitiveAttribute) (g
 // Returns the index of the new buffer view or an error if the data elements
 // don´t have all the same length.
 func WriteBufferViewInterleaved(doc *gltf.Document, data ...any) (int, error) {
-	return writeBufferViews(doc, gltf.TargetArrayBuffer, data...)
+	return lastWriter(doc).WriteBufferViewInterleaved(data...)
 }
 
 // WriteBufferView adds a new BufferView to doc
 // and fills the buffer with the data.
 // Returns the index of the new buffer view.
 func WriteBufferView(doc *gltf.Document, target gltf.Target, data any) int {
-	index, _ := writeBufferViews(doc, target, data)
-	return index
-}
-
-func writeBufferViews(doc *gltf.Document, target gltf.Target, data ...any) (int, error) {
-	var refLength, stride, size int
-	for i, d := range data {
-		c, a, l := binary.Type(d)
-		if i == 0 {
-			refLength = l
-		} else if refLength != l {
-			return 0, errors.New("gltf: interleaved data shall have the same number of elements in all chunks")
-		}
-		sizeOfElement := gltf.SizeOfElement(c, a)
-		size += l * sizeOfElement
-		if len(data) > 1 {
-			stride += sizeOfElement
-		} else if target == gltf.TargetArrayBuffer && c.ByteSize()*a.Components() != sizeOfElement {
-			stride = sizeOfElement
-		}
-	}
-	buffer := lastBuffer(doc)
-	offset := len(buffer.Data)
-	buffer.ByteLength += size
-	buffer.Data = append(buffer.Data, make([]byte, size)...)
-	dataOffset := offset
-	for _, d := range data {
-		// Cannot return error as the buffer has enough size and the data type is controlled.
-		_ = binary.Write(buffer.Data[dataOffset:], stride, d)
-		c, a, _ := binary.Type(d)
-		dataOffset += gltf.SizeOfElement(c, a)
-	}
-	bufferView := &gltf.BufferView{
-		Buffer:     len(doc.Buffers) - 1,
-		ByteLength: size,
-		ByteOffset: offset,
-		ByteStride: stride,
-		Target:     target,
-	}
-	doc.BufferViews = append(doc.BufferViews, bufferView)
-	return len(doc.BufferViews) - 1, nil
-}
-
-func ensurePadding(doc *gltf.Document) {
-	buffer := lastBuffer(doc)
-	padding := getPadding(len(buffer.Data))
-	buffer.Data = append(buffer.Data, make([]byte, padding)...)
-	buffer.ByteLength += padding
+	return lastWriter(doc).WriteBufferView(target, data)
 }
 
-func lastBuffer(doc *gltf.Document) *gltf.Buffer {
+// lastWriter returns a Writer that targets the last buffer of doc,
+// creating one if there is none.
+func lastWriter(doc *gltf.Document) *Writer {
 	if len(doc.Buffers) == 0 {
 		doc.Buffers = append(doc.Buffers, new(gltf.Buffer))
 	}
-	return doc.Buffers[len(doc.Buffers)-1]
+	return &Writer{doc: doc, buffer: len(doc.Buffers) - 1}
 }
 
 func getPadding(offset int) int {
@@ -776,6 +776,118 @@ func TestWriteInverseBindMatrices(t *testing.T) {
 	}
 }
 
+func TestWriteSparseAccessor(t *testing.T) {
+	baseDoc := func() *gltf.Document {
+		doc := gltf.NewDocument()
+		modeler.WriteAccessor(doc, gltf.TargetArrayBuffer, make([][3]float32, 300))
+		return doc
+	}
+	large := make([][3]float32, 300)
+	large[299] = [3]float32{1, 2, 3}
+	tests := []struct {
+		name       string
+		doc        *gltf.Document
+		base       *int
+		data       any
+		want       int
+		wantSparse *gltf.Sparse
+		wantView   *int
+		wantErr    bool
+	}{
+		{"zeros", gltf.NewDocument(), nil, [][3]float32{{}, {1, 2, 3}, {}, {}}, 0, &gltf.Sparse{
+			Count:   1,
+			Indices: gltf.SparseIndices{BufferView: 0, ComponentType: gltf.ComponentUbyte},
+			Values:  gltf.SparseValues{BufferView: 1},
+		}, nil, false},
+		{"unchanged", gltf.NewDocument(), nil, [][3]float32{{}, {}}, 0, nil, nil, false},
+		{"dense", gltf.NewDocument(), nil, [][3]float32{{1, 2, 3}, {4, 5, 6}}, 0, nil, gltf.Index(0), false},
+		{"base", baseDoc(), gltf.Index(0), large, 1, &gltf.Sparse{
+			Count:   1,
+			Indices: gltf.SparseIndices{BufferView: 1, ComponentType: gltf.ComponentUshort},
+			Values:  gltf.SparseValues{BufferView: 2},
+		}, gltf.Index(0), false},
+		{"baseMismatch", baseDoc(), gltf.Index(0), [][3]float32{{}}, 0, nil, nil, true},
+		{"baseOverflow", baseDoc(), gltf.Index(1), large, 0, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := modeler.WriteSparseAccessor(tt.doc, tt.base, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteSparseAccessor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.want != got {
+				t.Fatalf("WriteSparseAccessor() = %v, want %v", got, tt.want)
+			}
+			acr := tt.doc.Accessors[got]
+			if diff := deep.Equal(acr.Sparse, tt.wantSparse); diff != nil {
+				t.Errorf("WriteSparseAccessor() sparse = %v", diff)
+			}
+			if diff := deep.Equal(acr.BufferView, tt.wantView); diff != nil {
+				t.Errorf("WriteSparseAccessor() buffer view = %v", diff)
+			}
+			data, err := modeler.ReadAccessor(tt.doc, acr, nil)
+			if err != nil {
+				t.Fatalf("ReadAccessor() error = %v", err)
+			}
+			if diff := deep.Equal(data, tt.data); diff != nil {
+				t.Errorf("ReadAccessor() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestWriteMorphTargets(t *testing.T) {
+	doc := gltf.NewDocument()
+	prim := &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{}}
+	mesh := &gltf.Mesh{Primitives: []*gltf.Primitive{prim}}
+	err := modeler.WriteMorphTargets(doc, mesh, prim,
+		modeler.MorphTarget{Name: "smile", Position: [][3]float32{{}, {1, 2, 3}, {}, {}}},
+		modeler.MorphTarget{Position: [][3]float32{{1, 1, 1}, {-1, 0, 2}, {0, 1, 1}, {1, 0, 1}}, Normal: [][3]float32{{0, 0, 1}, {0, 1, 0}, {0, 0, 1}, {0, 1, 0}}},
+	)
+	if err != nil {
+		t.Fatalf("WriteMorphTargets() error = %v", err)
+	}
+	wantTargets := []gltf.PrimitiveAttributes{
+		{gltf.POSITION: 0},
+		{gltf.POSITION: 1, gltf.NORMAL: 2},
+	}
+	if diff := deep.Equal(prim.Targets, wantTargets); diff != nil {
+		t.Errorf("WriteMorphTargets() targets = %v", diff)
+	}
+	if doc.Accessors[0].Sparse == nil || doc.Accessors[1].Sparse != nil {
+		t.Error("WriteMorphTargets() sparse accessors not chosen by size")
+	}
+	if diff := deep.Equal([][]float64{doc.Accessors[1].Min, doc.Accessors[1].Max}, [][]float64{{-1, 0, 1}, {1, 1, 2}}); diff != nil {
+		t.Errorf("WriteMorphTargets() min max = %v", diff)
+	}
+	if doc.Accessors[2].Min != nil {
+		t.Error("WriteMorphTargets() NORMAL min defined")
+	}
+	wantMesh := &gltf.Mesh{
+		Primitives: mesh.Primitives,
+		Weights:    []float64{0, 0},
+		Extras:     map[string]any{"targetNames": []any{"smile", ""}},
+	}
+	if diff := deep.Equal(mesh, wantMesh); diff != nil {
+		t.Errorf("WriteMorphTargets() mesh = %v", diff)
+	}
+
+	if err := modeler.WriteMorphTargets(doc, mesh, prim, modeler.MorphTarget{Name: "frown"}); err == nil {
+		t.Error("WriteMorphTargets() expected error with mismatched weights")
+	}
+	prim2 := &gltf.Primitive{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: 0}}
+	if err := modeler.WriteMorphTargets(doc, mesh, prim2, modeler.MorphTarget{Position: make([][3]float32, 3)}, modeler.MorphTarget{}); err == nil {
+		t.Error("WriteMorphTargets() expected error with mismatched vertex count")
+	}
+	mesh.Extras = "extras"
+	if err := modeler.WriteMorphTargets(doc, mesh, prim2, modeler.MorphTarget{Name: "a"}, modeler.MorphTarget{Name: "b"}); err == nil {
+		t.Error("WriteMorphTargets() expected error with invalid extras")
+	}
+}
+
 type errReader struct{}
 
 func (r *errReader) Read(p []byte) (int, error) {
@@ -0,0 +1,499 @@
+package modeler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/binary"
+)
+
+// A Writer writes glTF entities into a specific buffer of a document,
+// so the data can be split into several files, such as geometry in one buffer
+// and animations in another.
+//
+// The package level Write* functions are equivalent
+// to the Writer methods targeting the last buffer of the document.
+// Buffer views holding accessors are always 4-byte aligned within the buffer.
+//
+// The options of the Writer are applied to all the accessors it writes,
+// including the interleaved ones, which do not accept options per call.
+type Writer struct {
+	doc    *gltf.Document
+	buffer int
+	opts   []WriteOption
+}
+
+// NewWriter returns a Writer that writes into the buffer of doc with index buffer.
+// Returns an error if the buffer does not exist.
+func NewWriter(doc *gltf.Document, buffer int, opts ...WriteOption) (*Writer, error) {
+	if buffer < 0 || buffer >= len(doc.Buffers) {
+		return nil, errors.New("gltf: buffer index overflows")
+	}
+	return &Writer{doc: doc, buffer: buffer, opts: opts}, nil
+}
+
+// NewBufferWriter adds a new empty buffer to doc with the given uri
+// and returns a Writer that writes into it.
+// An empty uri creates a buffer that is embedded when encoding.
+func NewBufferWriter(doc *gltf.Document, uri string, opts ...WriteOption) *Writer {
+	doc.Buffers = append(doc.Buffers, &gltf.Buffer{URI: uri})
+	return &Writer{doc: doc, buffer: len(doc.Buffers) - 1, opts: opts}
+}
+
+// Document returns the document w writes into.
+func (w *Writer) Document() *gltf.Document {
+	return w.doc
+}
+
+// Buffer returns the index of the buffer w writes into.
+func (w *Writer) Buffer() int {
+	return w.buffer
+}
+
+// WriteIndices adds a new INDICES accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteIndices(data any, opts ...WriteOption) int {
+	switch data.(type) {
+	case []uint16, []uint32:
+	default:
+		panic(fmt.Sprintf("modeler.WriteIndices: invalid type %T", data))
+	}
+	return w.WriteAccessor(gltf.TargetElementArrayBuffer, data, opts...)
+}
+
+// WriteNormal adds a new NORMAL accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteNormal(data [][3]float32, opts ...WriteOption) int {
+	return w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+}
+
+// WriteTangent adds a new TANGENT accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteTangent(data [][4]float32, opts ...WriteOption) int {
+	return w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+}
+
+// WriteTextureCoord adds a new TEXTURECOORD accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteTextureCoord(data any, opts ...WriteOption) int {
+	normalized, err := checkTextureCoord(data)
+	if err != nil {
+		panic(err)
+	}
+	index := w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+	w.doc.Accessors[index].Normalized = normalized
+	return index
+}
+
+// WriteWeights adds a new WEIGHTS accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteWeights(data any, opts ...WriteOption) int {
+	normalized, err := checkWeights(data)
+	if err != nil {
+		panic(err)
+	}
+	index := w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+	w.doc.Accessors[index].Normalized = normalized
+	return index
+}
+
+// WriteJoints adds a new JOINTS accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteJoints(data any, opts ...WriteOption) int {
+	err := checkJoints(data)
+	if err != nil {
+		panic(err)
+	}
+	return w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+}
+
+// WriteInverseBindMatrices adds a new inverse bind matrices accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteInverseBindMatrices(data [][4][4]float32, opts ...WriteOption) int {
+	return w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+}
+
+// WritePosition adds a new POSITION accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WritePosition(data [][3]float32) int {
+	index := w.WriteAccessor(gltf.TargetArrayBuffer, data)
+	min, max := minMaxFloat32(data)
+	w.doc.Accessors[index].Min = min[:]
+	w.doc.Accessors[index].Max = max[:]
+	return index
+}
+
+// WriteColor adds a new COLOR accessor to the document
+// and fills the writer buffer with data.
+// If success it returns the index of the new accessor.
+func (w *Writer) WriteColor(data any, opts ...WriteOption) int {
+	normalized, err := checkColor(data)
+	if err != nil {
+		panic(err)
+	}
+	index := w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+	w.doc.Accessors[index].Normalized = normalized
+	return index
+}
+
+// WriteImage adds a new image to the document
+// and fills the writer buffer with the image data.
+// If success it returns the index of the new image.
+func (w *Writer) WriteImage(name string, mimeType string, r io.Reader) (int, error) {
+	var data []byte
+	switch r := r.(type) {
+	case *bytes.Buffer:
+		data = r.Bytes()
+	default:
+		var err error
+		data, err = io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+	}
+	index := w.WriteBufferView(gltf.TargetNone, data)
+	w.doc.Images = append(w.doc.Images, &gltf.Image{
+		Name:       name,
+		MimeType:   mimeType,
+		BufferView: gltf.Index(index),
+	})
+	return len(w.doc.Images) - 1, nil
+}
+
+// WriteAccessor adds a new Accessor to the document
+// and fills the writer buffer with the data.
+// Returns the index of the new accessor.
+//
+// The other single accessor writers also accept opts,
+// which are applied once the accessor is written.
+func (w *Writer) WriteAccessor(target gltf.Target, data any, opts ...WriteOption) int {
+	w.ensurePadding()
+	index := w.WriteBufferView(target, data)
+	c, a, l := binary.Type(data)
+	w.doc.Accessors = append(w.doc.Accessors, &gltf.Accessor{
+		BufferView:    gltf.Index(index),
+		ByteOffset:    0,
+		ComponentType: c,
+		Type:          a,
+		Count:         l,
+	})
+	w.applyOptions(len(w.doc.Accessors)-1, opts)
+	return len(w.doc.Accessors) - 1
+}
+
+// WriteSparseAccessor adds a new Accessor to the document which stores data
+// as the elements that deviate from the accessor base.
+// If base is nil the elements are compared against zeros.
+// Returns the index of the new accessor.
+//
+// The elements that differ are stored in the smallest index component type
+// that can hold them. If storing them is not smaller than storing the whole data,
+// the accessor is written as a dense accessor with gltf.TargetArrayBuffer.
+//
+// base must have the same component type, type and count as data.
+// If base is sparse, only its buffer view is used as initialization value.
+func (w *Writer) WriteSparseAccessor(base *int, data any, opts ...WriteOption) (int, error) {
+	c, a, l := binary.Type(data)
+	sizeOfElement := gltf.SizeOfElement(c, a)
+	acr := &gltf.Accessor{
+		ComponentType: c,
+		Type:          a,
+		Count:         l,
+	}
+	initial := make([]byte, l*sizeOfElement)
+	if base != nil {
+		if *base < 0 || *base >= len(w.doc.Accessors) {
+			return 0, errors.New("gltf: accessor index overflows")
+		}
+		baseAcr := w.doc.Accessors[*base]
+		if baseAcr.ComponentType != c || baseAcr.Type != a || baseAcr.Count != l {
+			return 0, fmt.Errorf("gltf: base accessor %d does not match data of type %T and length %d", *base, data, l)
+		}
+		acr.BufferView, acr.ByteOffset, acr.Normalized = baseAcr.BufferView, baseAcr.ByteOffset, baseAcr.Normalized
+		if baseAcr.BufferView != nil {
+			baseData, err := ReadAccessor(w.doc, &gltf.Accessor{
+				BufferView:    baseAcr.BufferView,
+				ByteOffset:    baseAcr.ByteOffset,
+				ComponentType: c,
+				Type:          a,
+				Count:         l,
+			}, nil)
+			if err != nil {
+				return 0, err
+			}
+			_ = binary.Write(initial, 0, baseData)
+		}
+	}
+	current := make([]byte, l*sizeOfElement)
+	_ = binary.Write(current, 0, data)
+	var indices []int
+	for i := 0; i < l; i++ {
+		off := i * sizeOfElement
+		if !bytes.Equal(initial[off:off+sizeOfElement], current[off:off+sizeOfElement]) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		w.doc.Accessors = append(w.doc.Accessors, acr)
+		w.applyOptions(len(w.doc.Accessors)-1, opts)
+		return len(w.doc.Accessors) - 1, nil
+	}
+
+	indicesData, indicesType := sparseIndices(indices)
+	indicesSize := len(indices) * indicesType.ByteSize()
+	sparseSize := indicesSize + getPadding(indicesSize) + len(indices)*sizeOfElement
+	if sparseSize >= l*sizeOfElement {
+		index := w.WriteAccessor(gltf.TargetArrayBuffer, data, opts...)
+		w.doc.Accessors[index].Normalized = acr.Normalized
+		return index, nil
+	}
+	v := reflect.ValueOf(data)
+	values := reflect.MakeSlice(v.Type(), 0, len(indices))
+	for _, i := range indices {
+		values = reflect.Append(values, v.Index(i))
+	}
+	w.ensurePadding()
+	indicesView := w.WriteBufferView(gltf.TargetNone, indicesData)
+	w.ensurePadding()
+	valuesView := w.WriteBufferView(gltf.TargetNone, values.Interface())
+	acr.Sparse = &gltf.Sparse{
+		Count:   len(indices),
+		Indices: gltf.SparseIndices{BufferView: indicesView, ComponentType: indicesType},
+		Values:  gltf.SparseValues{BufferView: valuesView},
+	}
+	w.doc.Accessors = append(w.doc.Accessors, acr)
+	w.applyOptions(len(w.doc.Accessors)-1, opts)
+	return len(w.doc.Accessors) - 1, nil
+}
+
+// WriteMorphTargets adds the targets to primitive, which must belong to mesh,
+// writing each displacement as a sparse accessor when it is smaller than a dense one.
+// POSITION accessors define min and max, as required by the glTF spec.
+//
+// If mesh has no weights they are initialized to zero,
+// and if any target has a name, mesh.Extras["targetNames"] is set to the target names.
+// Returns an error if the number of targets does not match the ones already defined by mesh,
+// if the displacements do not have one element per vertex or if mesh.Extras is not a map.
+func (w *Writer) WriteMorphTargets(mesh *gltf.Mesh, primitive *gltf.Primitive, targets ...MorphTarget) error {
+	count := len(primitive.Targets) + len(targets)
+	if len(mesh.Weights) != 0 && len(mesh.Weights) != count {
+		return fmt.Errorf("gltf: mesh has %d weights but primitive has %d targets", len(mesh.Weights), count)
+	}
+	vertices := -1
+	if pos, ok := primitive.Attributes[gltf.POSITION]; ok && pos >= 0 && pos < len(w.doc.Accessors) {
+		vertices = w.doc.Accessors[pos].Count
+	}
+	for i, t := range targets {
+		for _, n := range []int{len(t.Position), len(t.Normal), len(t.Tangent)} {
+			if n == 0 {
+				continue
+			}
+			if vertices < 0 {
+				vertices = n
+			} else if n != vertices {
+				return fmt.Errorf("gltf: morph target %d has %d elements, want %d", i, n, vertices)
+			}
+		}
+	}
+	for _, t := range targets {
+		if t.Name != "" {
+			if err := setTargetNames(mesh, len(primitive.Targets), count, targets); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	for _, t := range targets {
+		attrs := make(gltf.PrimitiveAttributes)
+		for _, attr := range []struct {
+			name string
+			data [][3]float32
+		}{{gltf.POSITION, t.Position}, {gltf.NORMAL, t.Normal}, {gltf.TANGENT, t.Tangent}} {
+			if len(attr.data) == 0 {
+				continue
+			}
+			index, err := w.WriteSparseAccessor(nil, attr.data)
+			if err != nil {
+				return err
+			}
+			if attr.name == gltf.POSITION {
+				min, max := minMaxFloat32(attr.data)
+				w.doc.Accessors[index].Min = min[:]
+				w.doc.Accessors[index].Max = max[:]
+			}
+			attrs[attr.name] = index
+		}
+		primitive.Targets = append(primitive.Targets, attrs)
+	}
+	if len(mesh.Weights) == 0 {
+		mesh.Weights = make([]float64, count)
+	}
+	return nil
+}
+
+// WriteAccessorsInterleaved adds as many accessors as
+// elements in data all pointing to the same interleaved buffer view
+// and fills the writer buffer with the data.
+// Returns an slice with the indices of the newly created accessors,
+// with the same order as data or an error if the data elements
+// don´t have all the same length.
+// The options of w are applied to all the accessors.
+func (w *Writer) WriteAccessorsInterleaved(data ...any) ([]int, error) {
+	w.ensurePadding()
+	index, err := w.WriteBufferViewInterleaved(data...)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]int, len(data))
+	var byteOffset int
+	for i, d := range data {
+		c, t, l := binary.Type(d)
+		w.doc.Accessors = append(w.doc.Accessors, &gltf.Accessor{
+			BufferView:    gltf.Index(index),
+			ByteOffset:    byteOffset,
+			ComponentType: c,
+			Type:          t,
+			Count:         l,
+		})
+		byteOffset += gltf.SizeOfElement(c, t)
+		indices[i] = len(w.doc.Accessors) - 1
+		w.applyOptions(indices[i], nil)
+	}
+	return indices, nil
+}
+
+// WritePrimitiveAttributes write all the primitives attributes to the document as interleaved data.
+// Returns an attribute map that can be directly used as a primitive attributes.
+// The options of w are applied to all the attributes.
+func (w *Writer) WritePrimitiveAttributes(attr ...PrimitiveAttribute) (gltf.PrimitiveAttributes, error) {
+	type attrProps struct {
+		Name       string
+		Normalized bool
+	}
+	data := make([]any, 0, len(attr))
+	props := make([]attrProps, 0, len(attr))
+	var min, max [3]float64
+	var err error
+	for _, a := range attr {
+		if sliceLength(a.Data) == 0 {
+			continue
+		}
+		var normalized bool
+		switch a.Name {
+		case gltf.POSITION:
+			if v, ok := a.Data.([][3]float32); ok {
+				min, max = minMaxFloat32(v)
+			} else {
+				err = fmt.Errorf("invalid type %T", data)
+			}
+		case gltf.TEXCOORD_0, gltf.TEXCOORD_1:
+			normalized, err = checkTextureCoord(a.Data)
+		case gltf.WEIGHTS_0:
+			normalized, err = checkWeights(a.Data)
+		case gltf.JOINTS_0:
+			err = checkJoints(a.Data)
+		case gltf.COLOR_0:
+			normalized, err = checkColor(a.Data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+		data = append(data, a.Data)
+		props = append(props, attrProps{Name: a.Name, Normalized: normalized})
+	}
+	indices, err := w.WriteAccessorsInterleaved(data...)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make(gltf.PrimitiveAttributes, len(props))
+	for i, index := range indices {
+		prop := props[i]
+		attrs[prop.Name] = index
+		w.doc.Accessors[index].Normalized = prop.Normalized
+	}
+	if pos, ok := attrs[gltf.POSITION]; ok {
+		w.doc.Accessors[pos].Min = min[:]
+		w.doc.Accessors[pos].Max = max[:]
+	}
+	return attrs, nil
+}
+
+// WriteBufferViewInterleaved adds a new BufferView to the document
+// and fills the writer buffer with one or more vertex attribute.
+// If success it returns the index of the new buffer view.
+// Returns the index of the new buffer view or an error if the data elements
+// don´t have all the same length.
+func (w *Writer) WriteBufferViewInterleaved(data ...any) (int, error) {
+	return w.writeBufferViews(gltf.TargetArrayBuffer, data...)
+}
+
+// WriteBufferView adds a new BufferView to the document
+// and fills the writer buffer with the data.
+// Returns the index of the new buffer view.
+func (w *Writer) WriteBufferView(target gltf.Target, data any) int {
+	index, _ := w.writeBufferViews(target, data)
+	return index
+}
+
+// applyOptions applies the options of w and opts to the accessor at index.
+func (w *Writer) applyOptions(index int, opts []WriteOption) {
+	applyWriteOptions(w.doc, index, append(w.opts[:len(w.opts):len(w.opts)], opts...))
+}
+
+func (w *Writer) writeBufferViews(target gltf.Target, data ...any) (int, error) {
+	var refLength, stride, size int
+	for i, d := range data {
+		c, a, l := binary.Type(d)
+		if i == 0 {
+			refLength = l
+		} else if refLength != l {
+			return 0, errors.New("gltf: interleaved data shall have the same number of elements in all chunks")
+		}
+		sizeOfElement := gltf.SizeOfElement(c, a)
+		size += l * sizeOfElement
+		if len(data) > 1 {
+			stride += sizeOfElement
+		} else if target == gltf.TargetArrayBuffer && c.ByteSize()*a.Components() != sizeOfElement {
+			stride = sizeOfElement
+		}
+	}
+	buffer := w.doc.Buffers[w.buffer]
+	offset := len(buffer.Data)
+	buffer.ByteLength += size
+	buffer.Data = append(buffer.Data, make([]byte, size)...)
+	dataOffset := offset
+	for _, d := range data {
+		// Cannot return error as the buffer has enough size and the data type is controlled.
+		_ = binary.Write(buffer.Data[dataOffset:], stride, d)
+		c, a, _ := binary.Type(d)
+		dataOffset += gltf.SizeOfElement(c, a)
+	}
+	bufferView := &gltf.BufferView{
+		Buffer:     w.buffer,
+		ByteLength: size,
+		ByteOffset: offset,
+		ByteStride: stride,
+		Target:     target,
+	}
+	w.doc.BufferViews = append(w.doc.BufferViews, bufferView)
+	return len(w.doc.BufferViews) - 1, nil
+}
+
+func (w *Writer) ensurePadding() {
+	buffer := w.doc.Buffers[w.buffer]
+	padding := getPadding(len(buffer.Data))
+	buffer.Data = append(buffer.Data, make([]byte, padding)...)
+	buffer.ByteLength += padding
+}
@@ -0,0 +1,71 @@
+package modeler_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+)
+
+func TestWriter(t *testing.T) {
+	doc := gltf.NewDocument()
+	geometry := modeler.NewBufferWriter(doc, "geometry.bin")
+	anim := modeler.NewBufferWriter(doc, "animation.bin")
+	if geometry.Document() != doc || geometry.Buffer() != 0 || anim.Buffer() != 1 {
+		t.Fatalf("NewBufferWriter() = %d, %d", geometry.Buffer(), anim.Buffer())
+	}
+	geometry.WriteIndices([]uint16{0, 1, 2})
+	pos := geometry.WritePosition([][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	doc.Nodes = append(doc.Nodes, &gltf.Node{})
+	b := anim.NewAnimationBuilder("move")
+	if err := b.Translation(0, gltf.InterpolationLinear, []float32{0, 1}, [][3]float32{{0, 0, 0}, {1, 0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	// Package level functions keep writing into the last buffer.
+	last := modeler.WriteAccessor(doc, gltf.TargetNone, []float32{5})
+
+	if got := []string{doc.Buffers[0].URI, doc.Buffers[1].URI}; got[0] != "geometry.bin" || got[1] != "animation.bin" {
+		t.Errorf("Writer buffer URIs = %v", got)
+	}
+	var got []int
+	for _, bv := range doc.BufferViews {
+		got = append(got, bv.Buffer)
+	}
+	if diff := deep.Equal(got, []int{0, 0, 1, 1, 1}); diff != nil {
+		t.Errorf("Writer buffer views = %v", diff)
+	}
+	if off := doc.BufferViews[*doc.Accessors[pos].BufferView].ByteOffset; off != 8 {
+		t.Errorf("Writer position offset = %d, want 8", off)
+	}
+	if n := doc.Buffers[0].ByteLength; n != 44 || len(doc.Buffers[0].Data) != n {
+		t.Errorf("Writer geometry buffer length = %d, want 44", n)
+	}
+	if n := doc.Buffers[1].ByteLength; n != 36 || len(doc.Buffers[1].Data) != n {
+		t.Errorf("Writer animation buffer length = %d, want 36", n)
+	}
+	if bv := doc.Accessors[last].BufferView; bv == nil || doc.BufferViews[*bv].Buffer != 1 {
+		t.Errorf("WriteAccessor() did not write into the last buffer")
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	doc := gltf.NewDocument()
+	doc.Buffers = append(doc.Buffers, &gltf.Buffer{ByteLength: 1, Data: []byte{1}})
+	w, err := modeler.NewWriter(doc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteAccessor(gltf.TargetNone, []uint16{2})
+	if diff := deep.Equal(doc.Buffers[0].Data, []byte{1, 0, 0, 0, 2, 0}); diff != nil {
+		t.Errorf("Writer.WriteAccessor() = %v", diff)
+	}
+	for _, i := range []int{-1, 1} {
+		if _, err := modeler.NewWriter(doc, i); err == nil {
+			t.Errorf("NewWriter(%d) expected error", i)
+		}
+	}
+}
@@ -0,0 +1,819 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Extension names that only make sense in glTF 1.0 documents
+// and are consumed while upgrading them.
+const (
+	extBinaryGLTFV1      = "KHR_binary_glTF"
+	extMaterialsCommonV1 = "KHR_materials_common"
+	extMaterialsUnlit    = "KHR_materials_unlit"
+	binaryGLTFBufferV1   = "binary_glTF"
+)
+
+// isVersion1 reports whether version refers to a glTF 1.x asset.
+func isVersion1(version string) bool {
+	return version == "1" || strings.HasPrefix(version, "1.")
+}
+
+// peekVersion returns the asset.version property of a JSON-encoded document.
+func peekVersion(data []byte) string {
+	var tmp struct {
+		Asset struct {
+			Version json.RawMessage `json:"version"`
+		} `json:"asset"`
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return ""
+	}
+	var version string
+	if err := json.Unmarshal(tmp.Asset.Version, &version); err != nil {
+		// Some 1.0 exporters wrote the version as a number.
+		version = string(tmp.Asset.Version)
+	}
+	return version
+}
+
+// v1Dict is a JSON object whose keys are glTF 1.0 ids.
+// The keys order is preserved so ids are mapped to indices
+// in the same order they appear in the document.
+type v1Dict[T any] struct {
+	keys   []string
+	values []T
+}
+
+// UnmarshalJSON unmarshal the dictionary preserving the order of the keys.
+func (d *v1Dict[T]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return errors.New("gltf: glTF 1.0 dictionary must be a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		d.keys = append(d.keys, tok.(string))
+		d.values = append(d.values, v)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// indices maps each id to its position in the dictionary.
+func (d *v1Dict[T]) indices() map[string]int {
+	m := make(map[string]int, len(d.keys))
+	for i, k := range d.keys {
+		m[k] = i
+	}
+	return m
+}
+
+type v1Document struct {
+	Extensions     map[string]json.RawMessage `json:"extensions"`
+	Extras         any                        `json:"extras"`
+	ExtensionsUsed []string                   `json:"extensionsUsed"`
+	Accessors      v1Dict[v1Accessor]         `json:"accessors"`
+	Animations     v1Dict[v1Animation]        `json:"animations"`
+	Asset          v1Asset                    `json:"asset"`
+	Buffers        v1Dict[v1Buffer]           `json:"buffers"`
+	BufferViews    v1Dict[v1BufferView]       `json:"bufferViews"`
+	Cameras        v1Dict[*Camera]            `json:"cameras"`
+	Images         v1Dict[v1Image]            `json:"images"`
+	Materials      v1Dict[v1Material]         `json:"materials"`
+	Meshes         v1Dict[v1Mesh]             `json:"meshes"`
+	Nodes          v1Dict[v1Node]             `json:"nodes"`
+	Samplers       v1Dict[*Sampler]           `json:"samplers"`
+	Scene          string                     `json:"scene"`
+	Scenes         v1Dict[v1Scene]            `json:"scenes"`
+	Skins          v1Dict[v1Skin]             `json:"skins"`
+	Textures       v1Dict[v1Texture]          `json:"textures"`
+}
+
+type v1Asset struct {
+	Extras    any    `json:"extras"`
+	Copyright string `json:"copyright"`
+	Generator string `json:"generator"`
+}
+
+type v1Accessor struct {
+	Extras        any           `json:"extras"`
+	Name          string        `json:"name"`
+	BufferView    string        `json:"bufferView"`
+	ByteOffset    int           `json:"byteOffset"`
+	ByteStride    int           `json:"byteStride"`
+	ComponentType ComponentType `json:"componentType"`
+	Count         int           `json:"count"`
+	Type          AccessorType  `json:"type"`
+	Max           []float64     `json:"max"`
+	Min           []float64     `json:"min"`
+}
+
+type v1Animation struct {
+	Extras   any                   `json:"extras"`
+	Name     string                `json:"name"`
+	Channels []v1AnimationChannel  `json:"channels"`
+	Params   map[string]string     `json:"parameters"`
+	Samplers v1Dict[v1AnimSampler] `json:"samplers"`
+}
+
+type v1AnimationChannel struct {
+	Extras  any    `json:"extras"`
+	Sampler string `json:"sampler"`
+	Target  struct {
+		ID   string      `json:"id"`
+		Path TRSProperty `json:"path"`
+	} `json:"target"`
+}
+
+type v1AnimSampler struct {
+	Extras        any           `json:"extras"`
+	Input         string        `json:"input"`
+	Interpolation Interpolation `json:"interpolation"`
+	Output        string        `json:"output"`
+}
+
+type v1Buffer struct {
+	Extras     any    `json:"extras"`
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type v1BufferView struct {
+	Extras     any    `json:"extras"`
+	Name       string `json:"name"`
+	Buffer     string `json:"buffer"`
+	ByteOffset int    `json:"byteOffset"`
+	ByteLength int    `json:"byteLength"`
+	Target     Target `json:"target"`
+}
+
+type v1Image struct {
+	Extras     any    `json:"extras"`
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	Extensions struct {
+		Binary *struct {
+			BufferView string `json:"bufferView"`
+			MimeType   string `json:"mimeType"`
+		} `json:"KHR_binary_glTF"`
+	} `json:"extensions"`
+}
+
+type v1Material struct {
+	Extras     any                        `json:"extras"`
+	Name       string                     `json:"name"`
+	Values     map[string]json.RawMessage `json:"values"`
+	Extensions struct {
+		Common *struct {
+			Technique   string                     `json:"technique"`
+			DoubleSided bool                       `json:"doubleSided"`
+			Transparent bool                       `json:"transparent"`
+			Values      map[string]json.RawMessage `json:"values"`
+		} `json:"KHR_materials_common"`
+	} `json:"extensions"`
+}
+
+type v1Mesh struct {
+	Extras     any           `json:"extras"`
+	Name       string        `json:"name"`
+	Primitives []v1Primitive `json:"primitives"`
+}
+
+type v1Primitive struct {
+	Extras     any               `json:"extras"`
+	Attributes map[string]string `json:"attributes"`
+	Indices    string            `json:"indices"`
+	Material   string            `json:"material"`
+	Mode       *PrimitiveMode    `json:"mode"`
+}
+
+type v1Node struct {
+	Extras      any          `json:"extras"`
+	Name        string       `json:"name"`
+	Camera      string       `json:"camera"`
+	Children    []string     `json:"children"`
+	Skeletons   []string     `json:"skeletons"`
+	Skin        string       `json:"skin"`
+	JointName   string       `json:"jointName"`
+	Matrix      *[16]float64 `json:"matrix"`
+	Meshes      []string     `json:"meshes"`
+	Rotation    *[4]float64  `json:"rotation"`
+	Scale       *[3]float64  `json:"scale"`
+	Translation *[3]float64  `json:"translation"`
+}
+
+type v1Scene struct {
+	Extras any      `json:"extras"`
+	Name   string   `json:"name"`
+	Nodes  []string `json:"nodes"`
+}
+
+type v1Skin struct {
+	Extras              any          `json:"extras"`
+	Name                string       `json:"name"`
+	BindShapeMatrix     *[16]float64 `json:"bindShapeMatrix"`
+	InverseBindMatrices string       `json:"inverseBindMatrices"`
+	JointNames          []string     `json:"jointNames"`
+}
+
+type v1Texture struct {
+	Extras  any    `json:"extras"`
+	Name    string `json:"name"`
+	Sampler string `json:"sampler"`
+	Source  string `json:"source"`
+}
+
+// upgradeV1 converts the glTF 1.0 JSON in data into a 2.0 doc.
+//
+// Ids are mapped to indices in the order they appear in data.
+// Materials are converted to metallic-roughness using the values
+// of either KHR_materials_common or the material technique,
+// while techniques, programs and shaders are dropped.
+// Skins with a bind shape matrix other than the identity are not supported,
+// as it would have to be premultiplied into the inverse bind matrices.
+func upgradeV1(data []byte, doc *Document) error {
+	var v1 v1Document
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return err
+	}
+	u := &v1Upgrader{v1: &v1, doc: doc}
+	return u.upgrade()
+}
+
+type v1Upgrader struct {
+	v1  *v1Document
+	doc *Document
+
+	accessors, buffers, bufferViews, cameras map[string]int
+	images, materials, meshes, nodes         map[string]int
+	samplers, scenes, skins, textures        map[string]int
+	extensionsUsed                           []string
+}
+
+func (u *v1Upgrader) upgrade() error {
+	v1 := u.v1
+	u.accessors = v1.Accessors.indices()
+	u.bufferViews = v1.BufferViews.indices()
+	u.cameras = v1.Cameras.indices()
+	u.images = v1.Images.indices()
+	u.materials = v1.Materials.indices()
+	u.meshes = v1.Meshes.indices()
+	u.nodes = v1.Nodes.indices()
+	u.samplers = v1.Samplers.indices()
+	u.scenes = v1.Scenes.indices()
+	u.skins = v1.Skins.indices()
+	u.textures = v1.Textures.indices()
+
+	*u.doc = Document{
+		Extras: v1.Extras,
+		Asset: Asset{
+			Extras:    v1.Asset.Extras,
+			Copyright: v1.Asset.Copyright,
+			Generator: v1.Asset.Generator,
+			Version:   "2.0",
+		},
+	}
+	u.upgradeExtensions()
+	u.upgradeBuffers()
+	steps := []func() error{
+		u.upgradeBufferViews, u.upgradeAccessors, u.upgradeImages, u.upgradeSamplers, u.upgradeTextures,
+		u.upgradeMaterials, u.upgradeMeshes, u.upgradeCameras, u.upgradeNodes,
+		u.upgradeSkins, u.upgradeAnimations, u.upgradeScenes,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	u.doc.ExtensionsUsed = append(u.doc.ExtensionsUsed, u.extensionsUsed...)
+	return nil
+}
+
+func (u *v1Upgrader) index(ids map[string]int, kind, id string) (int, error) {
+	i, ok := ids[id]
+	if !ok {
+		return 0, fmt.Errorf("gltf: glTF 1.0 %s '%s' not found", kind, id)
+	}
+	return i, nil
+}
+
+func (u *v1Upgrader) optIndex(ids map[string]int, kind, id string) (*int, error) {
+	if id == "" {
+		return nil, nil
+	}
+	i, err := u.index(ids, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	return Index(i), nil
+}
+
+func (u *v1Upgrader) useExtension(name string) {
+	for _, ext := range u.extensionsUsed {
+		if ext == name {
+			return
+		}
+	}
+	u.extensionsUsed = append(u.extensionsUsed, name)
+}
+
+func (u *v1Upgrader) upgradeExtensions() {
+	for _, ext := range u.v1.ExtensionsUsed {
+		switch ext {
+		case extBinaryGLTFV1, extMaterialsCommonV1:
+		default:
+			u.useExtension(ext)
+		}
+	}
+	for key, value := range u.v1.Extensions {
+		if u.doc.Extensions == nil {
+			u.doc.Extensions = make(Extensions)
+		}
+		u.doc.Extensions[key] = value
+	}
+}
+
+func (u *v1Upgrader) upgradeBuffers() {
+	// The KHR_binary_glTF buffer must be the first one
+	// so the GLB body is loaded into it.
+	u.buffers = make(map[string]int, len(u.v1.Buffers.keys))
+	for pass := 0; pass < 2; pass++ {
+		for i, id := range u.v1.Buffers.keys {
+			if (id == binaryGLTFBufferV1) != (pass == 0) {
+				continue
+			}
+			b := u.v1.Buffers.values[i]
+			buf := &Buffer{
+				Extras:     b.Extras,
+				Name:       b.Name,
+				URI:        b.URI,
+				ByteLength: b.ByteLength,
+			}
+			if id == binaryGLTFBufferV1 {
+				buf.URI = ""
+			}
+			u.buffers[id] = len(u.doc.Buffers)
+			u.doc.Buffers = append(u.doc.Buffers, buf)
+		}
+	}
+}
+
+func (u *v1Upgrader) upgradeBufferViews() error {
+	for _, bv := range u.v1.BufferViews.values {
+		buffer, err := u.index(u.buffers, "buffer", bv.Buffer)
+		if err != nil {
+			return err
+		}
+		u.doc.BufferViews = append(u.doc.BufferViews, &BufferView{
+			Extras:     bv.Extras,
+			Name:       bv.Name,
+			Buffer:     buffer,
+			ByteOffset: bv.ByteOffset,
+			ByteLength: bv.ByteLength,
+			Target:     bv.Target,
+		})
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeAccessors() error {
+	// In glTF 1.0 the byte stride is defined per accessor,
+	// so buffer views shared by accessors with different strides are duplicated.
+	strided := make(map[[2]int]int)
+	for _, a := range u.v1.Accessors.values {
+		bv, err := u.index(u.bufferViews, "bufferView", a.BufferView)
+		if err != nil {
+			return err
+		}
+		if a.ByteStride != 0 && a.ByteStride != SizeOfElement(a.ComponentType, a.Type) {
+			key := [2]int{bv, a.ByteStride}
+			if idx, ok := strided[key]; ok {
+				bv = idx
+			} else {
+				if view := u.doc.BufferViews[bv]; view.ByteStride != 0 {
+					dup := *view
+					u.doc.BufferViews = append(u.doc.BufferViews, &dup)
+					bv = len(u.doc.BufferViews) - 1
+				}
+				u.doc.BufferViews[bv].ByteStride = a.ByteStride
+				strided[key] = bv
+			}
+		}
+		u.doc.Accessors = append(u.doc.Accessors, &Accessor{
+			Extras:        a.Extras,
+			Name:          a.Name,
+			BufferView:    Index(bv),
+			ByteOffset:    a.ByteOffset,
+			ComponentType: a.ComponentType,
+			Count:         a.Count,
+			Type:          a.Type,
+			Max:           a.Max,
+			Min:           a.Min,
+		})
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeImages() error {
+	for _, im := range u.v1.Images.values {
+		img := &Image{
+			Extras: im.Extras,
+			Name:   im.Name,
+			URI:    im.URI,
+		}
+		if bin := im.Extensions.Binary; bin != nil {
+			bv, err := u.index(u.bufferViews, "bufferView", bin.BufferView)
+			if err != nil {
+				return err
+			}
+			img.URI = ""
+			img.BufferView = Index(bv)
+			img.MimeType = bin.MimeType
+		}
+		u.doc.Images = append(u.doc.Images, img)
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeSamplers() error {
+	u.doc.Samplers = append(u.doc.Samplers, u.v1.Samplers.values...)
+	return nil
+}
+
+func (u *v1Upgrader) upgradeTextures() error {
+	for _, t := range u.v1.Textures.values {
+		sampler, err := u.optIndex(u.samplers, "sampler", t.Sampler)
+		if err != nil {
+			return err
+		}
+		source, err := u.optIndex(u.images, "image", t.Source)
+		if err != nil {
+			return err
+		}
+		u.doc.Textures = append(u.doc.Textures, &Texture{
+			Extras:  t.Extras,
+			Name:    t.Name,
+			Sampler: sampler,
+			Source:  source,
+		})
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeMaterials() error {
+	for _, m := range u.v1.Materials.values {
+		mat, err := u.material(&m)
+		if err != nil {
+			return err
+		}
+		u.doc.Materials = append(u.doc.Materials, mat)
+	}
+	return nil
+}
+
+// material converts the common material values (diffuse, emission,
+// shininess and transparency) into a metallic-roughness material.
+func (u *v1Upgrader) material(m *v1Material) (*Material, error) {
+	values, technique := m.Values, ""
+	mat := &Material{
+		Extras: m.Extras,
+		Name:   m.Name,
+		PBRMetallicRoughness: &PBRMetallicRoughness{
+			BaseColorFactor: &[4]float64{1, 1, 1, 1},
+			MetallicFactor:  Float(0),
+			RoughnessFactor: Float(1),
+		},
+	}
+	if common := m.Extensions.Common; common != nil {
+		values, technique = common.Values, strings.ToUpper(common.Technique)
+		mat.DoubleSided = common.DoubleSided
+		if common.Transparent {
+			mat.AlphaMode = AlphaBlend
+		}
+	}
+	pbr := mat.PBRMetallicRoughness
+	if color, tex, err := u.colorValue(values["diffuse"]); err != nil {
+		return nil, err
+	} else if tex != nil {
+		pbr.BaseColorTexture = tex
+	} else if color != nil {
+		pbr.BaseColorFactor = color
+	}
+	if color, tex, err := u.colorValue(values["emission"]); err != nil {
+		return nil, err
+	} else if tex != nil {
+		mat.EmissiveTexture = tex
+		mat.EmissiveFactor = [3]float64{1, 1, 1}
+	} else if color != nil {
+		mat.EmissiveFactor = [3]float64{color[0], color[1], color[2]}
+	}
+	var shininess float64
+	if err := unmarshalValue(values["shininess"], &shininess); err == nil && shininess >= 0 {
+		pbr.RoughnessFactor = Float(math.Sqrt(2 / (shininess + 2)))
+	}
+	var transparency float64
+	if err := unmarshalValue(values["transparency"], &transparency); err == nil && transparency < 1 {
+		pbr.BaseColorFactor[3] *= transparency
+	}
+	if pbr.BaseColorFactor[3] < 1 {
+		mat.AlphaMode = AlphaBlend
+	}
+	var doubleSided bool
+	if err := unmarshalValue(values["doubleSided"], &doubleSided); err == nil && doubleSided {
+		mat.DoubleSided = true
+	}
+	if technique == "CONSTANT" {
+		var unlit any = json.RawMessage("{}")
+		if extFactory, ok := queryExtension(extMaterialsUnlit); ok {
+			if ext, err := extFactory([]byte("{}")); err == nil {
+				unlit = ext
+			}
+		}
+		mat.Extensions = Extensions{extMaterialsUnlit: unlit}
+		u.useExtension(extMaterialsUnlit)
+	}
+	return mat, nil
+}
+
+var errNoValue = errors.New("gltf: no value")
+
+func unmarshalValue(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		return errNoValue
+	}
+	return json.Unmarshal(data, v)
+}
+
+// colorValue decodes a material value that is either a RGB(A) color or a texture id.
+func (u *v1Upgrader) colorValue(data json.RawMessage) (*[4]float64, *TextureInfo, error) {
+	var id string
+	if err := unmarshalValue(data, &id); err == nil {
+		tex, err := u.index(u.textures, "texture", id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &TextureInfo{Index: tex}, nil
+	}
+	var color []float64
+	if err := unmarshalValue(data, &color); err != nil || len(color) < 3 {
+		return nil, nil, nil
+	}
+	c := [4]float64{color[0], color[1], color[2], 1}
+	if len(color) > 3 {
+		c[3] = color[3]
+	}
+	return &c, nil, nil
+}
+
+// v1Semantic returns the glTF 2.0 attribute name of a glTF 1.0 semantic.
+func v1Semantic(semantic string) string {
+	name, set, _ := strings.Cut(semantic, "_")
+	switch name {
+	case "COLOR", "TEXCOORD":
+	case "JOINT":
+		name = "JOINTS"
+	case "WEIGHT":
+		name = "WEIGHTS"
+	default:
+		return semantic
+	}
+	if set == "" {
+		set = "0"
+	}
+	return name + "_" + set
+}
+
+func (u *v1Upgrader) upgradeMeshes() error {
+	for _, m := range u.v1.Meshes.values {
+		mesh := &Mesh{
+			Extras:     m.Extras,
+			Name:       m.Name,
+			Primitives: make([]*Primitive, 0, len(m.Primitives)),
+		}
+		for _, p := range m.Primitives {
+			prim := &Primitive{
+				Extras:     p.Extras,
+				Attributes: make(PrimitiveAttributes, len(p.Attributes)),
+			}
+			if p.Mode != nil {
+				prim.Mode = *p.Mode
+			}
+			for semantic, id := range p.Attributes {
+				a, err := u.index(u.accessors, "accessor", id)
+				if err != nil {
+					return err
+				}
+				prim.Attributes[v1Semantic(semantic)] = a
+			}
+			var err error
+			if prim.Indices, err = u.optIndex(u.accessors, "accessor", p.Indices); err != nil {
+				return err
+			}
+			if prim.Material, err = u.optIndex(u.materials, "material", p.Material); err != nil {
+				return err
+			}
+			mesh.Primitives = append(mesh.Primitives, prim)
+		}
+		u.doc.Meshes = append(u.doc.Meshes, mesh)
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeCameras() error {
+	u.doc.Cameras = append(u.doc.Cameras, u.v1.Cameras.values...)
+	return nil
+}
+
+func (u *v1Upgrader) upgradeNodes() error {
+	var extra []*Node
+	for _, n := range u.v1.Nodes.values {
+		node := &Node{
+			Extras:   n.Extras,
+			Name:     n.Name,
+			Matrix:   DefaultMatrix,
+			Rotation: DefaultRotation,
+			Scale:    DefaultScale,
+		}
+		if n.Matrix != nil {
+			node.Matrix = *n.Matrix
+		}
+		if n.Rotation != nil {
+			node.Rotation = *n.Rotation
+		}
+		if n.Scale != nil {
+			node.Scale = *n.Scale
+		}
+		if n.Translation != nil {
+			node.Translation = *n.Translation
+		}
+		var err error
+		if node.Camera, err = u.optIndex(u.cameras, "camera", n.Camera); err != nil {
+			return err
+		}
+		if node.Skin, err = u.optIndex(u.skins, "skin", n.Skin); err != nil {
+			return err
+		}
+		for _, id := range n.Children {
+			child, err := u.index(u.nodes, "node", id)
+			if err != nil {
+				return err
+			}
+			node.Children = append(node.Children, child)
+		}
+		// glTF 2.0 nodes can only reference one mesh,
+		// the rest are moved to new child nodes.
+		for i, id := range n.Meshes {
+			mesh, err := u.index(u.meshes, "mesh", id)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				node.Mesh = Index(mesh)
+				continue
+			}
+			node.Children = append(node.Children, len(u.v1.Nodes.values)+len(extra))
+			extra = append(extra, &Node{
+				Mesh:     Index(mesh),
+				Skin:     node.Skin,
+				Matrix:   DefaultMatrix,
+				Rotation: DefaultRotation,
+				Scale:    DefaultScale,
+			})
+		}
+		u.doc.Nodes = append(u.doc.Nodes, node)
+	}
+	u.doc.Nodes = append(u.doc.Nodes, extra...)
+	return nil
+}
+
+func (u *v1Upgrader) upgradeSkins() error {
+	joints := make(map[string]int)
+	for i, n := range u.v1.Nodes.values {
+		if n.JointName != "" {
+			joints[n.JointName] = i
+		}
+	}
+	for i, s := range u.v1.Skins.values {
+		if s.BindShapeMatrix != nil && *s.BindShapeMatrix != DefaultMatrix {
+			return fmt.Errorf("gltf: glTF 1.0 skin '%s' has an unsupported bindShapeMatrix", u.v1.Skins.keys[i])
+		}
+		skin := &Skin{
+			Extras: s.Extras,
+			Name:   s.Name,
+			Joints: make([]int, 0, len(s.JointNames)),
+		}
+		var err error
+		if skin.InverseBindMatrices, err = u.optIndex(u.accessors, "accessor", s.InverseBindMatrices); err != nil {
+			return err
+		}
+		for _, name := range s.JointNames {
+			joint, ok := joints[name]
+			if !ok {
+				return fmt.Errorf("gltf: glTF 1.0 joint '%s' not found", name)
+			}
+			skin.Joints = append(skin.Joints, joint)
+		}
+		u.doc.Skins = append(u.doc.Skins, skin)
+	}
+	// The skeleton roots are defined in the nodes that instantiate the skin.
+	for _, n := range u.v1.Nodes.values {
+		if n.Skin == "" || len(n.Skeletons) == 0 {
+			continue
+		}
+		skin := u.doc.Skins[u.skins[n.Skin]]
+		if skin.Skeleton != nil {
+			continue
+		}
+		skeleton, err := u.index(u.nodes, "node", n.Skeletons[0])
+		if err != nil {
+			return err
+		}
+		skin.Skeleton = Index(skeleton)
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeAnimations() error {
+	for _, a := range u.v1.Animations.values {
+		anim := &Animation{
+			Extras:   a.Extras,
+			Name:     a.Name,
+			Channels: make([]*AnimationChannel, 0, len(a.Channels)),
+			Samplers: make([]*AnimationSampler, 0, len(a.Samplers.values)),
+		}
+		param := func(name string) (int, error) {
+			return u.index(u.accessors, "accessor", a.Params[name])
+		}
+		for _, s := range a.Samplers.values {
+			input, err := param(s.Input)
+			if err != nil {
+				return err
+			}
+			output, err := param(s.Output)
+			if err != nil {
+				return err
+			}
+			anim.Samplers = append(anim.Samplers, &AnimationSampler{
+				Extras:        s.Extras,
+				Input:         input,
+				Interpolation: s.Interpolation,
+				Output:        output,
+			})
+		}
+		samplers := a.Samplers.indices()
+		for _, c := range a.Channels {
+			sampler, err := u.index(samplers, "animation sampler", c.Sampler)
+			if err != nil {
+				return err
+			}
+			node, err := u.optIndex(u.nodes, "node", c.Target.ID)
+			if err != nil {
+				return err
+			}
+			anim.Channels = append(anim.Channels, &AnimationChannel{
+				Extras:  c.Extras,
+				Sampler: sampler,
+				Target:  AnimationChannelTarget{Node: node, Path: c.Target.Path},
+			})
+		}
+		u.doc.Animations = append(u.doc.Animations, anim)
+	}
+	return nil
+}
+
+func (u *v1Upgrader) upgradeScenes() error {
+	for _, s := range u.v1.Scenes.values {
+		scene := &Scene{Extras: s.Extras, Name: s.Name}
+		for _, id := range s.Nodes {
+			node, err := u.index(u.nodes, "node", id)
+			if err != nil {
+				return err
+			}
+			scene.Nodes = append(scene.Nodes, node)
+		}
+		u.doc.Scenes = append(u.doc.Scenes, scene)
+	}
+	var err error
+	u.doc.Scene, err = u.optIndex(u.scenes, "scene", u.v1.Scene)
+	return err
+}
@@ -0,0 +1,196 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+const v1Triangle = `{
+	"asset": {"version": "1.0", "generator": "collada2gltf"},
+	"extensionsUsed": ["KHR_materials_common"],
+	"scene": "defaultScene",
+	"scenes": {"defaultScene": {"nodes": ["root"]}},
+	"nodes": {
+		"root": {"children": ["mesh_node", "joint"], "matrix": [1,0,0,0,0,1,0,0,0,0,1,0,0,0,0,1]},
+		"mesh_node": {"name": "Triangle", "meshes": ["mesh", "mesh"], "skin": "skin", "skeletons": ["joint"], "translation": [1, 2, 3]},
+		"joint": {"jointName": "Bone", "rotation": [0, 0, 0, 1]}
+	},
+	"skins": {"skin": {"bindShapeMatrix": [1,0,0,0,0,1,0,0,0,0,1,0,0,0,0,1], "inverseBindMatrices": "ibm", "jointNames": ["Bone"]}},
+	"meshes": {"mesh": {"primitives": [{"attributes": {"POSITION": "pos", "TEXCOORD_0": "uv", "JOINT": "joints", "WEIGHT": "weights"}, "indices": "idx", "material": "mat", "mode": 4}]}},
+	"materials": {
+		"mat": {"extensions": {"KHR_materials_common": {"technique": "BLINN", "values": {"diffuse": "tex", "emission": [0.1, 0.2, 0.3, 1], "shininess": 0, "transparency": 0.5}}}},
+		"unlit": {"extensions": {"KHR_materials_common": {"technique": "CONSTANT", "doubleSided": true, "values": {"diffuse": [0.5, 0.5, 0.5]}}}},
+		"technique": {"technique": "tech", "values": {"diffuse": [1, 0, 0, 1]}}
+	},
+	"textures": {"tex": {"sampler": "smp", "source": "img"}},
+	"samplers": {"smp": {"wrapS": 33071}},
+	"images": {"img": {"uri": "image.png"}},
+	"animations": {"anim": {
+		"channels": [{"sampler": "s", "target": {"id": "joint", "path": "rotation"}}],
+		"parameters": {"TIME": "time", "rotation": "rot"},
+		"samplers": {"s": {"input": "TIME", "interpolation": "LINEAR", "output": "rotation"}}
+	}},
+	"accessors": {
+		"idx": {"bufferView": "indices", "componentType": 5123, "count": 3, "type": "SCALAR"},
+		"pos": {"bufferView": "vertices", "byteStride": 20, "componentType": 5126, "count": 3, "type": "VEC3", "min": [0, 0, 0], "max": [1, 1, 0]},
+		"uv": {"bufferView": "vertices", "byteOffset": 12, "byteStride": 20, "componentType": 5126, "count": 3, "type": "VEC2"},
+		"joints": {"bufferView": "vertices", "byteOffset": 60, "byteStride": 32, "componentType": 5126, "count": 3, "type": "VEC4"},
+		"weights": {"bufferView": "vertices", "byteOffset": 108, "componentType": 5126, "count": 3, "type": "VEC4"},
+		"ibm": {"bufferView": "vertices", "byteOffset": 156, "componentType": 5126, "count": 1, "type": "MAT4"},
+		"time": {"bufferView": "vertices", "byteOffset": 220, "componentType": 5126, "count": 1, "type": "SCALAR"},
+		"rot": {"bufferView": "vertices", "byteOffset": 224, "componentType": 5126, "count": 1, "type": "VEC4"}
+	},
+	"bufferViews": {
+		"indices": {"buffer": "buf", "byteLength": 6, "target": 34963},
+		"vertices": {"buffer": "buf", "byteOffset": 8, "byteLength": 240, "target": 34962}
+	},
+	"buffers": {"buf": {"uri": "data:application/octet-stream;base64,AAABAAIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", "byteLength": 248}},
+	"techniques": {"tech": {"program": "program"}},
+	"programs": {"program": {}},
+	"shaders": {}
+}`
+
+func TestDecoder_Decode_V1(t *testing.T) {
+	deep.FloatPrecision = 5
+	want := &Document{
+		Asset: Asset{Version: "2.0", Generator: "collada2gltf"},
+		Accessors: []*Accessor{
+			{BufferView: Index(0), ComponentType: ComponentUshort, Count: 3, Type: AccessorScalar},
+			{BufferView: Index(1), ComponentType: ComponentFloat, Count: 3, Type: AccessorVec3, Min: []float64{0, 0, 0}, Max: []float64{1, 1, 0}},
+			{BufferView: Index(1), ByteOffset: 12, ComponentType: ComponentFloat, Count: 3, Type: AccessorVec2},
+			{BufferView: Index(2), ByteOffset: 60, ComponentType: ComponentFloat, Count: 3, Type: AccessorVec4},
+			{BufferView: Index(1), ByteOffset: 108, ComponentType: ComponentFloat, Count: 3, Type: AccessorVec4},
+			{BufferView: Index(1), ByteOffset: 156, ComponentType: ComponentFloat, Count: 1, Type: AccessorMat4},
+			{BufferView: Index(1), ByteOffset: 220, ComponentType: ComponentFloat, Count: 1, Type: AccessorScalar},
+			{BufferView: Index(1), ByteOffset: 224, ComponentType: ComponentFloat, Count: 1, Type: AccessorVec4},
+		},
+		Animations: []*Animation{{
+			Channels: []*AnimationChannel{{Sampler: 0, Target: AnimationChannelTarget{Node: Index(2), Path: TRSRotation}}},
+			Samplers: []*AnimationSampler{{Input: 6, Output: 7, Interpolation: InterpolationLinear}},
+		}},
+		BufferViews: []*BufferView{
+			{Buffer: 0, ByteLength: 6, Target: TargetElementArrayBuffer},
+			{Buffer: 0, ByteOffset: 8, ByteLength: 240, ByteStride: 20, Target: TargetArrayBuffer},
+			{Buffer: 0, ByteOffset: 8, ByteLength: 240, ByteStride: 32, Target: TargetArrayBuffer},
+		},
+		Buffers: []*Buffer{{ByteLength: 248, URI: "data:application/octet-stream;base64,AAABAAIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}},
+		Images:  []*Image{{URI: "image.png"}},
+		Materials: []*Material{
+			{
+				AlphaMode: AlphaBlend, EmissiveFactor: [3]float64{0.1, 0.2, 0.3},
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: &[4]float64{1, 1, 1, 0.5}, BaseColorTexture: &TextureInfo{Index: 0}, MetallicFactor: Float(0), RoughnessFactor: Float(1)},
+			},
+			{
+				DoubleSided: true, Extensions: Extensions{"KHR_materials_unlit": json.RawMessage("{}")},
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: &[4]float64{0.5, 0.5, 0.5, 1}, MetallicFactor: Float(0), RoughnessFactor: Float(1)},
+			},
+			{
+				PBRMetallicRoughness: &PBRMetallicRoughness{BaseColorFactor: &[4]float64{1, 0, 0, 1}, MetallicFactor: Float(0), RoughnessFactor: Float(1)},
+			},
+		},
+		Meshes: []*Mesh{{Primitives: []*Primitive{{
+			Attributes: PrimitiveAttributes{POSITION: 1, TEXCOORD_0: 2, JOINTS_0: 3, WEIGHTS_0: 4},
+			Indices:    Index(0), Material: Index(0), Mode: PrimitiveTriangles,
+		}}}},
+		ExtensionsUsed: []string{"KHR_materials_unlit"},
+		Nodes: []*Node{
+			{Children: []int{1, 2}, Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale},
+			{Name: "Triangle", Mesh: Index(0), Skin: Index(0), Children: []int{3}, Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale, Translation: [3]float64{1, 2, 3}},
+			{Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale},
+			{Mesh: Index(0), Skin: Index(0), Matrix: DefaultMatrix, Rotation: DefaultRotation, Scale: DefaultScale},
+		},
+		Samplers: []*Sampler{{WrapS: WrapClampToEdge}},
+		Scene:    Index(0),
+		Scenes:   []*Scene{{Nodes: []int{0}}},
+		Skins:    []*Skin{{InverseBindMatrices: Index(5), Skeleton: Index(2), Joints: []int{2}}},
+		Textures: []*Texture{{Sampler: Index(0), Source: Index(0)}},
+	}
+	want.Buffers[0].Data, _ = want.Buffers[0].marshalData()
+
+	doc := new(Document)
+	if err := NewDecoder(bytes.NewBufferString(v1Triangle)).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("Decoder.Decode() = %v", diff)
+	}
+}
+
+func TestDecoder_Decode_V1Binary(t *testing.T) {
+	content := []byte(`{"asset":{"version":"1.0"},` +
+		`"buffers":{"binary_glTF":{"uri":"data:,","byteLength":4},"ext":{"uri":"a.bin","byteLength":1}},` +
+		`"bufferViews":{"img":{"buffer":"binary_glTF","byteLength":4}},` +
+		`"images":{"img":{"uri":"data:,","extensions":{"KHR_binary_glTF":{"bufferView":"img","mimeType":"image/png"}}}}} `)
+	body := []byte{1, 2, 3, 4}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &glbHeader{
+		Magic:      glbHeaderMagic,
+		Version:    1,
+		Length:     uint32(20 + len(content) + len(body)),
+		JSONHeader: chunkHeader{Length: uint32(len(content)), Type: glbContentFormatJSONV1},
+	})
+	buf.Write(content)
+	buf.Write(body)
+
+	doc := new(Document)
+	if err := NewDecoder(&buf).Decode(doc); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want := &Document{
+		Asset:       Asset{Version: "2.0"},
+		Buffers:     []*Buffer{{ByteLength: 4, Data: body}, {ByteLength: 1, URI: "a.bin"}},
+		BufferViews: []*BufferView{{Buffer: 0, ByteLength: 4}},
+		Images:      []*Image{{BufferView: Index(0), MimeType: "image/png"}},
+	}
+	if diff := deep.Equal(doc, want); diff != nil {
+		t.Errorf("Decoder.Decode() = %v", diff)
+	}
+}
+
+func TestDecoder_Decode_V1Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"notDict", `{"asset":{"version":"1.0"},"nodes":[]}`},
+		{"missingBuffer", `{"asset":{"version":"1.0"},"bufferViews":{"bv":{"buffer":"b"}}}`},
+		{"missingBufferView", `{"asset":{"version":"1.0"},"accessors":{"a":{"bufferView":"bv"}}}`},
+		{"missingJoint", `{"asset":{"version":"1.0"},"skins":{"s":{"jointNames":["a"]}}}`},
+		{"bindShapeMatrix", `{"asset":{"version":"1.0"},"skins":{"s":{"bindShapeMatrix":[2,0,0,0,0,2,0,0,0,0,2,0,0,0,0,1]}}}`},
+		{"missingTexture", `{"asset":{"version":"1.0"},"materials":{"m":{"values":{"diffuse":"t"}}}}`},
+		{"missingScene", `{"asset":{"version":"1.0"},"scene":"s"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDecoder(bytes.NewBufferString(tt.data)).Decode(new(Document)); err == nil {
+				t.Error("Decoder.Decode() expected error")
+			}
+		})
+	}
+}
+
+func Test_v1Semantic(t *testing.T) {
+	tests := []struct {
+		semantic string
+		want     string
+	}{
+		{"POSITION", POSITION},
+		{"TEXCOORD", TEXCOORD_0},
+		{"TEXCOORD_1", TEXCOORD_1},
+		{"COLOR", COLOR_0},
+		{"JOINT", JOINTS_0},
+		{"WEIGHT", WEIGHTS_0},
+		{"_CUSTOM", "_CUSTOM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.semantic, func(t *testing.T) {
+			if got := v1Semantic(tt.semantic); got != tt.want {
+				t.Errorf("v1Semantic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package gltf
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// OpenZip decodes the glTF or GLB document stored in the zip archive r,
+// reading its external resources from the archive.
+//
+// The document is the .gltf or .glb file closest to the archive root,
+// which must be unique.
+func OpenZip(r *zip.Reader) (*Document, error) {
+	name, err := zipDocumentName(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fsys, err := fs.Sub(r, path.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	doc := new(Document)
+	if err = NewDecoderFS(f, fsys).Decode(doc); err != nil {
+		doc = nil
+	}
+	return doc, err
+}
+
+// zipDocumentName returns the name of the root document of r.
+func zipDocumentName(r *zip.Reader) (string, error) {
+	var (
+		name      string
+		depth     = -1
+		ambiguous bool
+	)
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !fs.ValidPath(f.Name) {
+			continue
+		}
+		if ext := strings.ToLower(path.Ext(f.Name)); ext != ".gltf" && ext != ".glb" {
+			continue
+		}
+		d := strings.Count(f.Name, "/")
+		switch {
+		case depth < 0 || d < depth:
+			name, depth, ambiguous = f.Name, d, false
+		case d == depth:
+			ambiguous = true
+		}
+	}
+	if depth < 0 {
+		return "", errors.New("gltf: no .gltf or .glb file found in zip archive")
+	}
+	if ambiguous {
+		return "", errors.New("gltf: multiple .gltf or .glb files found in zip archive root")
+	}
+	return name, nil
+}
+
+// A ZipFS is a CreateFS that writes files into a zip archive.
+// Files can't be opened back, Open always fails.
+//
+// As zip archives are written sequentially, each file must be completely written
+// before creating the next one, which is how Encoder uses it.
+type ZipFS struct {
+	w *zip.Writer
+}
+
+// NewZipFS returns a ZipFS that writes into w.
+// The caller is responsible for closing w once done.
+func NewZipFS(w *zip.Writer) *ZipFS {
+	return &ZipFS{w: w}
+}
+
+// Open implements fs.FS.
+func (z *ZipFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Create creates the named file in the archive.
+func (z *ZipFS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	w, err := z.w.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return nopWriteCloser{w}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package gltf
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func newZip(t *testing.T, files map[string][]byte) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+func TestOpenZip(t *testing.T) {
+	box := readFile("testdata/Box With Spaces/glTF/Box With Spaces.gltf")
+	bin := readFile("testdata/Box With Spaces/glTF/Box With Spaces.bin")
+	glb := readFile("testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb")
+	tests := []struct {
+		name    string
+		files   map[string][]byte
+		want    string
+		wantErr bool
+	}{
+		{"root", map[string][]byte{"Box With Spaces.gltf": box, "Box With Spaces.bin": bin}, "testdata/Box With Spaces/glTF/Box With Spaces.gltf", false},
+		{"nested", map[string][]byte{"a/Box With Spaces.gltf": box, "a/Box With Spaces.bin": bin, "a/b/c.glb": glb}, "testdata/Box With Spaces/glTF/Box With Spaces.gltf", false},
+		{"glb", map[string][]byte{"a.GLB": glb, "readme.txt": nil}, "testdata/BoxVertexColors/glTF-Binary/BoxVertexColors.glb", false},
+		{"empty", map[string][]byte{"readme.txt": nil}, "", true},
+		{"ambiguous", map[string][]byte{"a.gltf": box, "b.glb": glb}, "", true},
+		{"missingBuffer", map[string][]byte{"a.gltf": box}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OpenZip(newZip(t, tt.files))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenZip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want, err := Open(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := deep.Equal(got, want); diff != nil {
+				t.Errorf("OpenZip() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestZipFS(t *testing.T) {
+	doc, err := Open("testdata/Box With Spaces/glTF/Box With Spaces.gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zfs := NewZipFS(zw)
+	w, err := zfs.Create("Box With Spaces.gltf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEncoderFS(w, zfs)
+	e.AsBinary = false
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := OpenZip(zr)
+	if err != nil {
+		t.Fatalf("OpenZip() error = %v", err)
+	}
+	if diff := deep.Equal(got, doc); diff != nil {
+		t.Errorf("OpenZip() = %v", diff)
+	}
+	if _, err := zfs.Open("Box With Spaces.gltf"); err == nil {
+		t.Error("ZipFS.Open() expected error")
+	}
+	if _, err := zfs.Create("../a.bin"); err == nil {
+		t.Error("ZipFS.Create() expected error")
+	}
+}